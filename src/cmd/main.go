@@ -39,12 +39,25 @@ func Run(content embed.FS) {
 				Action: func(c *cli.Context) error {
 					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#66b2ff")).Render("[Configuration file path]"), varibale.ConfigFilea)
 					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#ffcc66")).Render("[Hotkeys file path]"), varibale.HotkeysFilea)
+					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#99ccff")).Render("[Workspaces file path]"), varibale.WorkspacesFilea)
 					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#66ff66")).Render("[Log file path]"), varibale.LogFilea)
 					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#ff9999")).Render("[Configuration directory path]"), varibale.SuperFileMainDir)
 					fmt.Printf("%-*s %s\n", 55, lipgloss.NewStyle().Foreground(lipgloss.Color("#ff66ff")).Render("[Data directory path]"), varibale.SuperFileDataDir)
 					return nil
 				},
 			},
+			{
+				Name:      "query",
+				Aliases:   []string{"q"},
+				Usage:     "Print the visited directories matching the keywords, most frecent first",
+				ArgsUsage: "[keywords...]",
+				Action: func(c *cli.Context) error {
+					for _, location := range internal.QueryFrecency(c.Args().Slice()) {
+						fmt.Println(location)
+					}
+					return nil
+				},
+			},
 		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
@@ -53,6 +66,17 @@ func Run(content embed.FS) {
 				Usage:   "Adds any missing hotkeys to the hotkey config file",
 				Value:   false,
 			},
+			&cli.BoolFlag{
+				Name:    "restore",
+				Aliases: []string{"r"},
+				Usage:   "Restore the file panels, locations and cursors of the last session",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:    "workspace",
+				Aliases: []string{"ws"},
+				Usage:   "Open the workspace with this name from workspaces.toml",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			path := ""
@@ -63,6 +87,8 @@ func Run(content embed.FS) {
 			InitConfigFile()
 
 			varibale.FixHotkeys = c.Bool("fix-hotkeys")
+			varibale.RestoreSession = c.Bool("restore")
+			varibale.OpenWorkspace = c.String("workspace")
 
 			firstUse := checkFirstUse()
 
@@ -108,6 +134,12 @@ func InitConfigFile() {
 	// Create files
 	if err := createFiles(
 		varibale.PinnedFilea,
+		varibale.SortOptionsFilea,
+		varibale.FrecencyFilea,
+		varibale.MarksFilea,
+		varibale.SessionFilea,
+		varibale.LayoutFilea,
+		varibale.JournalFilea,
 		varibale.ToggleDotFilea,
 		varibale.LogFilea,
 		varibale.ThemeFileVersiona,
@@ -123,6 +155,10 @@ func InitConfigFile() {
 	if err := writeConfigFile(varibale.HotkeysFilea, internal.HotkeysTomlString); err != nil {
 		log.Fatalln("Error writing config file:", err)
 	}
+
+	if err := writeConfigFile(varibale.WorkspacesFilea, internal.WorkspacesTomlString); err != nil {
+		log.Fatalln("Error writing config file:", err)
+	}
 }
 
 // Helper functions
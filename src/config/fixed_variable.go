@@ -20,11 +20,20 @@ var (
 	ThemeFileVersiona string = SuperFileDataDir + "/themeFileVersion"
 	FirstUseChecka    string = SuperFileDataDir + "/firstUseCheck"
 	PinnedFilea       string = SuperFileDataDir + "/pinned.json"
+	SortOptionsFilea  string = SuperFileDataDir + "/sortOptions.json"
+	FrecencyFilea     string = SuperFileDataDir + "/frecency.json"
+	MarksFilea        string = SuperFileDataDir + "/marks.json"
+	SessionFilea      string = SuperFileStateDir + "/session.json"
+	LayoutFilea       string = SuperFileDataDir + "/layout.json"
+	JournalFilea      string = SuperFileStateDir + "/journal.json"
 	ConfigFilea       string = SuperFileMainDir + "/config.toml"
 	HotkeysFilea      string = SuperFileMainDir + "/hotkeys.toml"
+	WorkspacesFilea   string = SuperFileMainDir + "/workspaces.toml"
 	ToggleDotFilea    string = SuperFileDataDir + "/toggleDotFile"
 	LogFilea          string = SuperFileStateDir + "/superfile.log"
 	FixHotkeys        bool   = false
+	RestoreSession    bool   = false
+	OpenWorkspace     string = ""
 )
 
 const (
@@ -31,5 +31,6 @@ func InitIcon(nerdfont bool) {
 		InOperation = ""
 		Directory = ""
 		Search = ""
+		GitBranch = ""
 	}
 }
@@ -37,6 +37,7 @@ var (
 	InOperation string = "󰥔"
 	Directory   string = ""
 	Search      string = ""
+	GitBranch   string = ""
 )
 
 /*
@@ -43,6 +43,14 @@ func initialConfig(dir string) (toggleDotFileBool bool, firstFilePanelDir string
 	}
 	LoadThemeConfig()
 
+	loadSortOptions()
+
+	loadFrecencyDatabase()
+
+	loadMarks()
+
+	loadJournal()
+
 	if Config.Metadata {
 		et, err = exiftool.NewExiftool()
 		if err != nil {
@@ -50,6 +58,8 @@ func initialConfig(dir string) (toggleDotFileBool bool, firstFilePanelDir string
 		}
 	}
 
+	dirWatcher = newDirectoryWatcher()
+
 	if dir != "" {
 		firstFilePanelDir, err = filepath.Abs(dir)
 	} else {
@@ -100,6 +110,17 @@ func loadConfigFile() {
 		fmt.Println(loadConfigError("sidebar_width"))
 		os.Exit(0)
 	}
+
+	if Config.FlattenMaxDepth < 1 {
+		fmt.Println(loadConfigError("flatten_max_depth"))
+		os.Exit(0)
+	}
+
+	detailsColumns, err = parseDetailsViewFormat(Config.DetailsViewFormat)
+	if err != nil {
+		fmt.Println(loadConfigError("details_view_format"))
+		os.Exit(0)
+	}
 }
 
 func loadHotkeysFile() {
@@ -199,6 +220,12 @@ func LoadAllDefaultConfig(content embed.FS) {
 	}
 	ConfigTomlString = string(temp)
 
+	temp, err = content.ReadFile("src/superfile_config/workspaces.toml")
+	if err != nil {
+		return
+	}
+	WorkspacesTomlString = string(temp)
+
 	temp, err = content.ReadFile("src/superfile_config/theme/catppuccin.toml")
 	if err != nil {
 		return
@@ -51,6 +51,12 @@ var (
 
 	helpMenuHotkeyColor lipgloss.Color
 	helpMenuTitleColor  lipgloss.Color
+
+	gitModifiedColor   lipgloss.Color
+	gitStagedColor     lipgloss.Color
+	gitUntrackedColor  lipgloss.Color
+	gitIgnoredColor    lipgloss.Color
+	gitConflictedColor lipgloss.Color
 )
 
 // Theme configuration
@@ -111,6 +117,13 @@ type ThemeType struct {
 
 	HelpMenuHotkey string `toml:"help_menu_hotkey"`
 	HelpMenuTitle  string `toml:"help_menu_title"`
+
+	// Git Status
+	GitModified   string `toml:"git_modified"`
+	GitStaged     string `toml:"git_staged"`
+	GitUntracked  string `toml:"git_untracked"`
+	GitIgnored    string `toml:"git_ignored"`
+	GitConflicted string `toml:"git_conflicted"`
 }
 
 // Configuration settings
@@ -120,13 +133,23 @@ type ConfigType struct {
 	AutoCheckUpdate        bool   `toml:"auto_check_update" comment:"\nAuto check for update"`
 	CdOnQuit               bool   `toml:"cd_on_quit" comment:"\nCd on quit (For more details, please check out https://superfile.netlify.app/configure/superfile-config/#cd_on_quit)"`
 	DefaultOpenFilePreview bool   `toml:"default_open_file_preview" comment:"\nWhether to open file preview automatically every time superfile is opened."`
+	DefaultMillerColumns   bool   `toml:"default_miller_columns" comment:"\nWhether to open superfile in the miller columns layout (parent directory, current directory and file preview) instead of the file panels side by side."`
+	RestoreSession         bool   `toml:"restore_session" comment:"\nWhether to restore the file panels, locations and cursors of the last session every time superfile is opened without a path."`
 	DefaultDirectory       string `toml:"default_directory" comment:"\nThe path of the first file panel when superfile is opened."`
 	FileSizeUseSI          bool   `toml:"file_size_use_si" comment:"\nDisplay file sizes using powers of 1000 (kB, MB, GB) instead of powers of 1024 (KiB, MiB, GiB)."`
-
-	Nerdfont              bool `toml:"nerdfont" comment:"\n================   Style =================\n\n If you don't have or don't want Nerdfont installed you can turn this off"`
-	TransparentBackground bool `toml:"transparent_background" comment:"\nSet transparent background or not (this only work when your terminal background is transparent)"`
-	FilePreviewWidth      int  `toml:"file_preview_width" comment:"\nFile preview width allow '0' (this mean same as file panel),'x' x must be less than 10 and greater than 1 (This means that the width of the file preview will be one xth of the total width.)"`
-	SidebarWidth          int  `toml:"sidebar_width" comment:"\nThe length of the sidebar. If you don't want to display the sidebar, you can input 0 directly. If you want to display the value, please place it in the range of 3-20."`
+	CopyPreserveMode       bool   `toml:"copy_preserve_mode" comment:"\nWhat copies and moves between disks keep of the original, like cp -a. The owner is only kept when superfile is allowed to change it."`
+	CopyPreserveTimes      bool   `toml:"copy_preserve_times"`
+	CopyPreserveXattrs     bool   `toml:"copy_preserve_xattrs"`
+	CopyPreserveOwnership  bool   `toml:"copy_preserve_ownership"`
+	CopyFollowSymlinks     bool   `toml:"copy_follow_symlinks" comment:"\nCopy what symlinks point to instead of the symlinks themselves."`
+
+	Nerdfont              bool   `toml:"nerdfont" comment:"\n================   Style =================\n\n If you don't have or don't want Nerdfont installed you can turn this off"`
+	TransparentBackground bool   `toml:"transparent_background" comment:"\nSet transparent background or not (this only work when your terminal background is transparent)"`
+	FilePreviewWidth      int    `toml:"file_preview_width" comment:"\nFile preview width allow '0' (this mean same as file panel),'x' x must be less than 10 and greater than 1 (This means that the width of the file preview will be one xth of the total width.)"`
+	StackFilePreviewWidth int    `toml:"stack_file_preview_width" comment:"\nPut the file preview below the file panels when the terminal is narrower than this many columns. 0 keeps it next to the file panels."`
+	SidebarWidth          int    `toml:"sidebar_width" comment:"\nThe length of the sidebar. If you don't want to display the sidebar, you can input 0 directly. If you want to display the value, please place it in the range of 3-20."`
+	DetailsViewFormat     string `toml:"details_view_format" comment:"\nColumns of the details view and their order, separated by ','. Available columns: name, size, mtime, permissions, owner, link"`
+	FlattenMaxDepth       int    `toml:"flatten_max_depth" comment:"\nHow many levels of subdirectories the flattened view lists, must be at least 1"`
 
 	BorderTop         string `toml:"border_top" comment:"\nBorder style"`
 	BorderBottom      string `toml:"border_bottom"`
@@ -155,6 +178,10 @@ type HotkeysType struct {
 	NextFilePanel          []string `toml:"next_file_panel"`
 	PreviousFilePanel      []string `toml:"previous_file_panel"`
 	ToggleFilePreviewPanel []string `toml:"toggle_file_preview_panel"`
+	ToggleMillerColumns    []string `toml:"toggle_miller_columns"`
+	WidenFilePanel         []string `toml:"widen_file_panel"`
+	NarrowFilePanel        []string `toml:"narrow_file_panel"`
+	ResetLayout            []string `toml:"reset_layout"`
 
 	FocusOnProcessBar []string `toml:"focus_on_process_bar" comment:"change focus"`
 	FocusOnSidebar    []string `toml:"focus_on_sidebar"`
@@ -167,6 +194,9 @@ type HotkeysType struct {
 	PasteItems  []string `toml:"paste_items"`
 	CutItems    []string `toml:"cut_items"`
 	DeleteItems []string `toml:"delete_items"`
+	Undo        []string `toml:"undo"`
+	Redo        []string `toml:"redo"`
+	OpenJournal []string `toml:"open_journal"`
 
 	ExtractFile  []string `toml:"extract_file" comment:"compress and extract"`
 	CompressFile []string `toml:"compress_file"`
@@ -174,17 +204,36 @@ type HotkeysType struct {
 	OpenFileWithEditor             []string `toml:"open_file_with_editor" comment:"editor"`
 	OpenCurrentDirectoryWithEditor []string `toml:"open_current_directory_with_editor"`
 
-	PinnedDirectory []string `toml:"pinned_directory" comment:"other"`
-	ToggleDotFile   []string `toml:"toggle_dot_file"`
-	ChangePanelMode []string `toml:"change_panel_mode"`
-	OpenHelpMenu    []string `toml:"open_help_menu"`
-	OpenCommandLine []string `toml:"open_command_line"`
+	PinnedDirectory   []string `toml:"pinned_directory" comment:"other"`
+	ToggleDotFile     []string `toml:"toggle_dot_file"`
+	NextSortType      []string `toml:"next_sort_type"`
+	ReverseSortOrder  []string `toml:"reverse_sort_order"`
+	ChangePanelMode   []string `toml:"change_panel_mode"`
+	ToggleDetailsView []string `toml:"toggle_details_view"`
+	ToggleTreeView    []string `toml:"toggle_tree_view"`
+	ToggleFlatten     []string `toml:"toggle_flatten"`
+	OpenDiskUsage     []string `toml:"open_disk_usage"`
+	OpenWorkspaces    []string `toml:"open_workspaces"`
+	OpenHelpMenu      []string `toml:"open_help_menu"`
+	OpenCommandLine   []string `toml:"open_command_line"`
 
 	ConfirmTyping []string `toml:"confirm_typing" comment:"=================================================================================================\nTyping hotkeys (can conflict with all hotkeys)"`
 	CancelTyping  []string `toml:"cancel_typing"`
 
 	ParentDirectory []string `toml:"parent_directory" comment:"=================================================================================================\nNormal mode hotkeys (can conflict with other modes, cannot conflict with global hotkeys)"`
 	SearchBar       []string `toml:"search_bar"`
+	RecursiveSearch []string `toml:"recursive_search"`
+	ContentSearch   []string `toml:"content_search"`
+	FilterBar       []string `toml:"filter_bar"`
+	HistoryBack     []string `toml:"history_back"`
+	HistoryForward  []string `toml:"history_forward"`
+	OpenHistory     []string `toml:"open_history"`
+	OpenJump        []string `toml:"open_jump"`
+	GoToPath        []string `toml:"go_to_path"`
+	TypeAheadFind   []string `toml:"type_ahead_find"`
+	SetMark         []string `toml:"set_mark"`
+	JumpToMark      []string `toml:"jump_to_mark"`
+	OpenMarks       []string `toml:"open_marks"`
 
 	FilePanelSelectModeItemsSelectDown []string `toml:"file_panel_select_mode_items_select_down" comment:"=================================================================================================\nSelect mode hotkeys (can conflict with other modes, cananot conflict with global hotkeys)"`
 	FilePanelSelectModeItemsSelectUp   []string `toml:"file_panel_select_mode_items_select_up"`
@@ -0,0 +1,399 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/lithammer/shortuuid"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+const (
+	// Stop searching after this many matching lines
+	contentSearchMaxResults = 10000
+	// Files bigger than this are not searched
+	contentSearchMaxFileSize = 16 * 1024 * 1024
+	// Send the found lines to the model at least this often
+	contentSearchSendInterval = 100 * time.Millisecond
+	// Lines shown in the file preview above the line of the opened result
+	contentSearchPreviewContext = 3
+)
+
+// A search through the content of the files in a subtree running in the background
+type contentSearch struct {
+	id      string
+	done    bool
+	results chan contentSearchMsg
+	cancel  context.CancelFunc
+}
+
+// Message with the lines found by a content search since the last message
+type contentSearchMsg struct {
+	searchId string
+	results  []contentSearchResult
+	done     bool
+}
+
+// Compile the pattern of a content search, patterns starting with "re:" are regular
+// expressions, other patterns are literal and case insensitive when they are all lowercase
+func compileContentSearchPattern(pattern string) (*regexp.Regexp, error) {
+	if regexPattern, isRegex := strings.CutPrefix(pattern, "re:"); isRegex {
+		return regexp.Compile(regexPattern)
+	}
+
+	literal := regexp.QuoteMeta(pattern)
+	if strings.ToLower(pattern) == pattern {
+		literal = "(?i)" + literal
+	}
+	return regexp.Compile(literal)
+}
+
+// Start searching the text files under the location, the found lines are streamed in with contentSearchMsg
+func startContentSearch(location string, displayDotFile bool, pattern *regexp.Regexp) *contentSearch {
+	ctx, cancel := context.WithCancel(context.Background())
+	search := &contentSearch{
+		id:      shortuuid.New(),
+		results: make(chan contentSearchMsg, 1),
+		cancel:  cancel,
+	}
+	go search.run(ctx, location, displayDotFile, pattern)
+	return search
+}
+
+// Walk the subtree and search every text file until it's done or cancelled
+func (search *contentSearch) run(ctx context.Context, location string, displayDotFile bool, pattern *regexp.Regexp) {
+	defer close(search.results)
+
+	found := []contentSearchResult{}
+	total := 0
+	lastSend := time.Now()
+
+	send := func(done bool) bool {
+		select {
+		case search.results <- contentSearchMsg{searchId: search.id, results: found, done: done}:
+			found = []contentSearchResult{}
+			lastSend = time.Now()
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	err := filepath.WalkDir(location, func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// Skip directories that can't be read instead of stopping the search
+			if entry != nil && entry.IsDir() && path != location {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path != location && !displayDotFile && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		for _, result := range searchFileContent(ctx, path, pattern, contentSearchMaxResults-total) {
+			found = append(found, result)
+			total++
+		}
+		if total >= contentSearchMaxResults {
+			return filepath.SkipAll
+		}
+
+		if len(found) > 0 && time.Since(lastSend) >= contentSearchSendInterval {
+			if !send(false) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		outPutLog("Content search walk directory error", location, err)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	send(true)
+}
+
+// Return at most limit lines of the file matching the pattern, binary files are skipped
+func searchFileContent(ctx context.Context, location string, pattern *regexp.Regexp, limit int) (results []contentSearchResult) {
+	fileInfo, err := os.Stat(location)
+	if err != nil || fileInfo.Size() == 0 || fileInfo.Size() > contentSearchMaxFileSize {
+		return nil
+	}
+	if textFile, err := isTextFile(location); err != nil || !textFile {
+		return nil
+	}
+
+	file, err := os.Open(location)
+	if err != nil {
+		outPutLog("Content search open file error", location, err)
+		return nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() && len(results) < limit {
+		lineNumber++
+		if lineNumber%1000 == 0 && ctx.Err() != nil {
+			return nil
+		}
+		line := scanner.Text()
+		if !pattern.MatchString(line) {
+			continue
+		}
+		results = append(results, contentSearchResult{
+			location: location,
+			line:     lineNumber,
+			snippet:  strings.TrimSpace(strings.ReplaceAll(line, "\t", "    ")),
+		})
+	}
+	return results
+}
+
+// Wait for the next lines found by the content search
+func waitForContentSearch(search *contentSearch) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-search.results
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Add the lines found by the content search to the modal
+func (m *model) handleContentSearch(msg contentSearchMsg) tea.Cmd {
+	search := m.contentSearchModal.search
+	if search == nil || search.id != msg.searchId {
+		// The search was cancelled, nobody is waiting for it anymore
+		return nil
+	}
+
+	m.contentSearchModal.results = append(m.contentSearchModal.results, msg.results...)
+	if msg.done {
+		search.done = true
+		return nil
+	}
+	return waitForContentSearch(search)
+}
+
+// Open the content search modal for the current directory
+func (m *model) openContentSearchModal() {
+	m.fileModel.filePreview.scrollLocation = ""
+	m.fileModel.filePreview.scrollLine = 0
+	ti := textinput.New()
+	ti.Cursor.Style = modalCursorStyle
+	ti.Cursor.TextStyle = modalStyle
+	ti.TextStyle = modalStyle
+	ti.Prompt = filePanelTopDirectoryIconStyle.Render(icon.Search + icon.Space)
+	ti.Cursor.Blink = true
+	ti.Placeholder = "Text to search, start with \"re:\" for a regular expression"
+	ti.PlaceholderStyle = modalStyle
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = m.helpMenu.width - 6
+
+	m.contentSearchModal = contentSearchModal{
+		open:      true,
+		location:  m.fileModel.filePanels[m.filePanelFocusIndex].location,
+		textInput: ti,
+	}
+	m.firstTextInput = true
+}
+
+// Close the content search modal and stop the search
+func (m *model) closeContentSearchModal() {
+	if m.contentSearchModal.search != nil {
+		m.contentSearchModal.search.cancel()
+	}
+	m.contentSearchModal = contentSearchModal{}
+}
+
+// Start searching for the typed pattern, or open the selected result when it's already searched
+func (m *model) confirmContentSearch() tea.Cmd {
+	pattern := m.contentSearchModal.textInput.Value()
+	if pattern == m.contentSearchModal.pattern && len(m.contentSearchModal.results) > 0 {
+		m.openContentSearchResult()
+		return nil
+	}
+	if pattern == "" {
+		return nil
+	}
+
+	if m.contentSearchModal.search != nil {
+		m.contentSearchModal.search.cancel()
+	}
+	m.contentSearchModal.pattern = pattern
+	m.contentSearchModal.results = nil
+	m.contentSearchModal.cursor = 0
+	m.contentSearchModal.renderIndex = 0
+	m.contentSearchModal.err = ""
+	m.contentSearchModal.search = nil
+
+	regex, err := compileContentSearchPattern(pattern)
+	if err != nil {
+		m.contentSearchModal.err = err.Error()
+		return nil
+	}
+	m.contentSearchModal.search = startContentSearch(m.contentSearchModal.location, m.toggleDotFile, regex)
+	return waitForContentSearch(m.contentSearchModal.search)
+}
+
+// Go to the file of the selected result and show the matching line in the file preview
+func (m *model) openContentSearchResult() {
+	result := m.contentSearchModal.results[m.contentSearchModal.cursor]
+	m.closeContentSearchModal()
+
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	panel.directoryRecord[panel.location] = directoryRecord{
+		directoryCursor: panel.cursor,
+		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
+	}
+	panel.location = filepath.Dir(result.location)
+	panel.targetFile = result.location
+	panel.cursor = 0
+	panel.render = 0
+	panel.searchBar.SetValue("")
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+
+	if !m.fileModel.filePreview.open {
+		m.toggleFilePreviewPanel()
+	}
+	m.fileModel.filePreview.scrollLocation = result.location
+	m.fileModel.filePreview.scrollLine = result.line
+}
+
+// Forget the line of the opened result once the cursor left its file
+func (m *model) clearPreviewScroll() {
+	preview := &m.fileModel.filePreview
+	if preview.scrollLocation == "" {
+		return
+	}
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	// The cursor moves to the result once its directory is loaded
+	if panel.targetFile == preview.scrollLocation {
+		return
+	}
+	if panel.cursor >= 0 && panel.cursor < len(panel.element) && panel.element[panel.cursor].location == preview.scrollLocation {
+		return
+	}
+	preview.scrollLocation = ""
+	preview.scrollLine = 0
+}
+
+// Lines to skip at the start of the file preview, so the line of the opened result is near the top
+func (preview filePreviewPanel) firstLine(location string) int {
+	if location != preview.scrollLocation {
+		return 0
+	}
+	return max(0, preview.scrollLine-1-contentSearchPreviewContext)
+}
+
+// Content search modal list up
+func (m *model) contentSearchModalListUp() {
+	if m.contentSearchModal.cursor > 0 {
+		m.contentSearchModal.cursor--
+		if m.contentSearchModal.cursor < m.contentSearchModal.renderIndex {
+			m.contentSearchModal.renderIndex--
+		}
+	} else if len(m.contentSearchModal.results) > 0 {
+		m.contentSearchModal.cursor = len(m.contentSearchModal.results) - 1
+		m.contentSearchModal.renderIndex = max(0, len(m.contentSearchModal.results)-contentSearchModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Content search modal list down
+func (m *model) contentSearchModalListDown() {
+	if m.contentSearchModal.cursor < len(m.contentSearchModal.results)-1 {
+		m.contentSearchModal.cursor++
+		if m.contentSearchModal.cursor >= m.contentSearchModal.renderIndex+contentSearchModalListHeight(m.helpMenu.height) {
+			m.contentSearchModal.renderIndex++
+		}
+	} else {
+		m.contentSearchModal.cursor = 0
+		m.contentSearchModal.renderIndex = 0
+	}
+}
+
+// Lines of the content search modal left for the results
+func contentSearchModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the content search modal
+func (m model) contentSearchModalRender() string {
+	width := m.helpMenu.width
+	content := " " + m.contentSearchModal.textInput.View() + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	if m.contentSearchModal.err != "" {
+		content += processErrorStyle.Render(" " + icon.Error + "  " + truncateText(m.contentSearchModal.err, width-5, "..."))
+	} else if len(m.contentSearchModal.results) == 0 && m.contentSearchModal.search != nil {
+		if m.contentSearchModal.search.done {
+			content += modalStyle.Render(" " + icon.Error + "  No match found")
+		} else {
+			content += modalStyle.Render(" " + icon.InOperation + "  Searching...")
+		}
+	}
+
+	results := m.contentSearchModal.results
+	for i := m.contentSearchModal.renderIndex; i < m.contentSearchModal.renderIndex+contentSearchModalListHeight(m.helpMenu.height) && i < len(results); i++ {
+		if i != m.contentSearchModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.contentSearchModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		relativePath, err := filepath.Rel(m.contentSearchModal.location, results[i].location)
+		if err != nil {
+			relativePath = results[i].location
+		}
+		position := relativePath + ":" + strconv.Itoa(results[i].line) + ": "
+		position = truncateTextBeginning(position, (width-2)/2, "...")
+		snippetWidth := width - 2 - ansi.StringWidth(position)
+		snippet := results[i].snippet
+		if ansi.StringWidth(snippet) > snippetWidth {
+			snippet = truncateText(snippet, snippetWidth, "...")
+		}
+		content += cursor + helpMenuHotkeyStyle.Render(position) + modalStyle.Render(snippet)
+	}
+
+	count := "0/0"
+	if len(results) > 0 {
+		count = fmt.Sprintf("%d/%d", m.contentSearchModal.cursor+1, len(results))
+	}
+	if m.contentSearchModal.search != nil && !m.contentSearchModal.search.done {
+		count += " ..."
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompileContentSearchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		line    string
+		match   bool
+	}{
+		{"todo", "// TODO: fix this", true},
+		{"TODO", "// todo: fix this", false},
+		{"a.b", "axb", false},
+		{"a.b", "a.b", true},
+		{"re:^func [A-Z]", "func Exported() {", true},
+		{"re:^func [A-Z]", "func unexported() {", false},
+	}
+
+	for _, tc := range testCases {
+		pattern, err := compileContentSearchPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("compile %q: %v", tc.pattern, err)
+		}
+		if pattern.MatchString(tc.line) != tc.match {
+			t.Errorf("pattern %q on %q: expected match %t", tc.pattern, tc.line, tc.match)
+		}
+	}
+
+	if _, err := compileContentSearchPattern("re:("); err == nil {
+		t.Error("invalid regular expression should return an error")
+	}
+}
+
+func TestContentSearch(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("first line\nsecond needle\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "code.go"), []byte("package sub\n\n// needle here\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "binary"), []byte("needle\x00\x01\x02"), 0644)
+
+	pattern, _ := compileContentSearchPattern("needle")
+	m := model{}
+	m.contentSearchModal.search = startContentSearch(dir, false, pattern)
+	cmd := waitForContentSearch(m.contentSearchModal.search)
+	for cmd != nil {
+		cmd = m.handleContentSearch(cmd().(contentSearchMsg))
+	}
+
+	results := m.contentSearchModal.results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, expected 2 (binary files are skipped)", len(results))
+	}
+	if results[0].location != filepath.Join(dir, "notes.txt") || results[0].line != 2 || results[0].snippet != "second needle" {
+		t.Errorf("unexpected first result %+v", results[0])
+	}
+	if results[1].location != filepath.Join(dir, "sub", "code.go") || results[1].line != 3 {
+		t.Errorf("unexpected second result %+v", results[1])
+	}
+
+	if limited := searchFileContent(context.Background(), filepath.Join(dir, "notes.txt"), pattern, 0); len(limited) != 0 {
+		t.Errorf("got %d results over the limit", len(limited))
+	}
+}
+
+func TestClearPreviewScroll(t *testing.T) {
+	m := model{fileModel: fileModel{
+		filePanels: []filePanel{{
+			element: []element{{location: "/dir/result.txt"}, {location: "/dir/other.txt"}},
+		}},
+		filePreview: filePreviewPanel{scrollLocation: "/dir/result.txt", scrollLine: 40},
+	}}
+
+	m.clearPreviewScroll()
+	if m.fileModel.filePreview.firstLine("/dir/result.txt") == 0 {
+		t.Error("the result should stay scrolled while the cursor is on it")
+	}
+
+	m.fileModel.filePanels[0].cursor = 1
+	m.clearPreviewScroll()
+	m.fileModel.filePanels[0].cursor = 0
+	if m.fileModel.filePreview.firstLine("/dir/result.txt") != 0 {
+		t.Error("the result should open at the top once the cursor left it")
+	}
+}
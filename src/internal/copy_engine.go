@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// What a copy keeps of the original, like cp -a. The attributes that can not be kept, e.g. the
+// owner when superfile doesn't run as root, are left as the copy got them
+type copyOptions struct {
+	mode      bool
+	times     bool
+	xattrs    bool
+	ownership bool
+	// Copy what symlinks point to instead of the links
+	followSymlinks bool
+}
+
+// Return the copy options of the config
+func configCopyOptions() copyOptions {
+	return copyOptions{
+		mode:           Config.CopyPreserveMode,
+		times:          Config.CopyPreserveTimes,
+		xattrs:         Config.CopyPreserveXattrs,
+		ownership:      Config.CopyPreserveOwnership,
+		followSymlinks: Config.CopyFollowSymlinks,
+	}
+}
+
+// Return the file info of the path, of the symlink itself unless symlinks are followed
+func (options copyOptions) stat(path string) (os.FileInfo, error) {
+	if options.followSymlinks {
+		return os.Stat(path)
+	}
+	return os.Lstat(path)
+}
+
+// Copy the file or directory with everything in it, copied is called after every file
+func copyTree(src string, dst string, options copyOptions, copied func(path string) error) error {
+	return copyTreeIn(src, dst, options, copied, map[string]bool{})
+}
+
+// Copy the tree, parents are the directories being copied above it to stop at symlink loops
+func copyTreeIn(src string, dst string, options copyOptions, copied func(path string) error, parents map[string]bool) error {
+	info, err := options.stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := copyEntry(src, dst, info, options); err != nil {
+			return err
+		}
+		return copied(src)
+	}
+
+	if options.followSymlinks {
+		realPath, err := filepath.EvalSymlinks(src)
+		if err != nil {
+			return err
+		}
+		if parents[realPath] {
+			return fmt.Errorf("symlink loop at %s", src)
+		}
+		parents[realPath] = true
+		defer delete(parents, realPath)
+	}
+
+	// The directory stays writable until its content is copied, the mode is set after
+	perm := os.FileMode(0777)
+	if options.mode {
+		perm = 0700
+	}
+	if err := os.Mkdir(dst, perm); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err := copyTreeIn(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), options, copied, parents)
+		if err != nil {
+			return err
+		}
+	}
+	// Copying the content changes the modification time, so the times are set last
+	return copyAttributes(src, dst, info, options)
+}
+
+// Copy a file that is not a directory: regular files, symlinks, named pipes and device nodes
+func copyEntry(src string, dst string, info os.FileInfo, options copyOptions) error {
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dst); err != nil {
+			return err
+		}
+	case mode.IsRegular():
+		if err := copyFileContent(src, dst, info, options); err != nil {
+			return err
+		}
+	case mode&(os.ModeNamedPipe|os.ModeDevice) != 0:
+		// Reading a named pipe or a device would wait for a writer or never end, they are created instead
+		if err := copySpecialFile(src, dst, info); err != nil {
+			return err
+		}
+	default:
+		// Sockets belong to the program listening on them, a copy would be of no use
+		outPutLog("Copy entry function skip socket or irregular file", src)
+		return nil
+	}
+	return copyAttributes(src, dst, info, options)
+}
+
+// Copy the content of the regular file into a new file
+func copyFileContent(src string, dst string, info os.FileInfo, options copyOptions) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	perm := os.FileMode(0666)
+	if options.mode {
+		// The owner can write the copy until the mode is set
+		perm = info.Mode().Perm() | 0200
+	}
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Give the copy the attributes of the original the options keep. The owner is changed first,
+// changing it clears the setuid and setgid bits
+func copyAttributes(src string, dst string, info os.FileInfo, options copyOptions) error {
+	symlink := info.Mode()&os.ModeSymlink != 0
+	if options.ownership {
+		if err := copyOwnership(src, dst, options.followSymlinks); err != nil {
+			return err
+		}
+	}
+	if options.xattrs {
+		if err := copyXattrs(src, dst, options.followSymlinks); err != nil {
+			return err
+		}
+	}
+	// The mode of a symlink is not used
+	if options.mode && !symlink {
+		if err := os.Chmod(dst, info.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)); err != nil {
+			return err
+		}
+	}
+	if options.times {
+		if err := copyTimes(src, dst, info, options.followSymlinks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
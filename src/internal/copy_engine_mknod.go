@@ -0,0 +1,10 @@
+//go:build !windows && !freebsd
+
+package internal
+
+import "golang.org/x/sys/unix"
+
+// Create the device node, mknod takes the device number as an int here
+func makeDeviceNode(dst string, mode uint32, dev uint64) error {
+	return unix.Mknod(dst, mode, int(dev))
+}
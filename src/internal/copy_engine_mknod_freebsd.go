@@ -0,0 +1,8 @@
+package internal
+
+import "golang.org/x/sys/unix"
+
+// Create the device node, mknod takes the device number as an uint64 on freebsd
+func makeDeviceNode(dst string, mode uint32, dev uint64) error {
+	return unix.Mknod(dst, mode, dev)
+}
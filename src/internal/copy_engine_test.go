@@ -0,0 +1,115 @@
+//go:build !windows
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCopyTreePreservesAttributes(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(src, "sub", "script.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/script.sh", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := unix.Mkfifo(filepath.Join(src, "fifo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	hasXattr := unix.Setxattr(script, "user.superfile", []byte("kept"), 0) == nil
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, path := range []string{script, filepath.Join(src, "sub")} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(src, "sub"), 0550); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(filepath.Join(src, "sub"), 0755)
+
+	dst := filepath.Join(dir, "dst")
+	options := copyOptions{mode: true, times: true, xattrs: true, ownership: true}
+	copied := 0
+	err := copyTree(src, dst, options, func(path string) error {
+		copied++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(filepath.Join(dst, "sub"), 0755)
+	if copied != 3 {
+		t.Errorf("expected 3 copied files, got %d", copied)
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "sub", "script.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0750 || !info.ModTime().Equal(old) {
+		t.Errorf("script should keep mode 0750 and mtime %v, got %v and %v", old, info.Mode().Perm(), info.ModTime())
+	}
+	info, err = os.Stat(filepath.Join(dst, "sub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0550 || !info.ModTime().Equal(old) {
+		t.Errorf("directory should keep mode 0550 and mtime %v, got %v and %v", old, info.Mode().Perm(), info.ModTime())
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "sub/script.sh" {
+		t.Errorf("symlink should be copied as a link, got %q %v", target, err)
+	}
+	if info, err := os.Lstat(filepath.Join(dst, "fifo")); err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		t.Errorf("fifo should be recreated, got %v", err)
+	}
+	if hasXattr {
+		value := make([]byte, 16)
+		size, err := unix.Getxattr(filepath.Join(dst, "sub", "script.sh"), "user.superfile", value)
+		if err != nil || string(value[:size]) != "kept" {
+			t.Errorf("extended attribute should be copied, got %q %v", value[:size], err)
+		}
+	}
+}
+
+func TestCopyTreeFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "target"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "target"), filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(src, filepath.Join(src, "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	noop := func(path string) error { return nil }
+	if err := copyTree(src, filepath.Join(dir, "dst"), copyOptions{followSymlinks: true}, noop); err == nil {
+		t.Error("following a symlink loop should fail")
+	}
+
+	os.Remove(filepath.Join(src, "loop"))
+	if err := copyTree(src, filepath.Join(dir, "followed"), copyOptions{followSymlinks: true}, noop); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(filepath.Join(dir, "followed", "link"))
+	if err != nil || !info.Mode().IsRegular() {
+		t.Errorf("followed symlink should be copied as a regular file, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build !windows
+
+package internal
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Return the stat of the original, of the symlink itself unless symlinks are followed
+func copySourceStat(src string, followSymlinks bool) (unix.Stat_t, error) {
+	var stat unix.Stat_t
+	if followSymlinks {
+		return stat, unix.Stat(src, &stat)
+	}
+	return stat, unix.Lstat(src, &stat)
+}
+
+// Return whether the error only means the attribute can't be kept here, e.g. the owner of a copy
+// made without root or extended attributes on a filesystem without them
+func copyAttributeNotPermitted(err error) bool {
+	return errors.Is(err, unix.EPERM) || errors.Is(err, unix.EACCES) ||
+		errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EOPNOTSUPP)
+}
+
+// Create a named pipe or device node like the original
+func copySpecialFile(src string, dst string, info os.FileInfo) error {
+	perm := uint32(info.Mode().Perm())
+	if info.Mode()&os.ModeNamedPipe != 0 {
+		return unix.Mkfifo(dst, perm)
+	}
+
+	stat, err := copySourceStat(src, true)
+	if err != nil {
+		return err
+	}
+	nodeType := uint32(unix.S_IFBLK)
+	if info.Mode()&os.ModeCharDevice != 0 {
+		nodeType = unix.S_IFCHR
+	}
+	// Only root can create device nodes
+	return makeDeviceNode(dst, nodeType|perm, uint64(stat.Rdev))
+}
+
+// Give the copy the owner and group of the original, when we are allowed to
+func copyOwnership(src string, dst string, followSymlinks bool) error {
+	stat, err := copySourceStat(src, followSymlinks)
+	if err != nil {
+		return err
+	}
+	err = os.Lchown(dst, int(stat.Uid), int(stat.Gid))
+	if err != nil && copyAttributeNotPermitted(err) {
+		return nil
+	}
+	return err
+}
+
+// Copy the extended attributes the copy is allowed to have
+func copyXattrs(src string, dst string, followSymlinks bool) error {
+	listxattr, getxattr := unix.Llistxattr, unix.Lgetxattr
+	if followSymlinks {
+		listxattr, getxattr = unix.Listxattr, unix.Getxattr
+	}
+
+	size, err := listxattr(src, nil)
+	if err != nil || size == 0 {
+		if err != nil && copyAttributeNotPermitted(err) {
+			return nil
+		}
+		return err
+	}
+	names := make([]byte, size)
+	size, err = listxattr(src, names)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Split(strings.TrimRight(string(names[:size]), "\x00"), "\x00") {
+		size, err := getxattr(src, name, nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, size)
+		size, err = getxattr(src, name, value)
+		if err != nil {
+			return err
+		}
+		err = unix.Lsetxattr(dst, name, value[:size], 0)
+		if err != nil && !copyAttributeNotPermitted(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Give the copy the access and modification time of the original
+func copyTimes(src string, dst string, info os.FileInfo, followSymlinks bool) error {
+	stat, err := copySourceStat(src, followSymlinks)
+	if err != nil {
+		return err
+	}
+	times := []unix.Timespec{stat.Atim, stat.Mtim}
+	err = unix.UtimesNanoAt(unix.AT_FDCWD, dst, times, unix.AT_SYMLINK_NOFOLLOW)
+	if err != nil && copyAttributeNotPermitted(err) {
+		return nil
+	}
+	return err
+}
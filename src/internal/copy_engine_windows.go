@@ -0,0 +1,31 @@
+//go:build windows
+
+package internal
+
+import (
+	"fmt"
+	"os"
+)
+
+// Named pipes and device nodes can not be created on windows
+func copySpecialFile(src string, dst string, info os.FileInfo) error {
+	return fmt.Errorf("can not copy %s: special files are not supported on windows", src)
+}
+
+// Give the copy the owner of the original (not supported on windows)
+func copyOwnership(src string, dst string, followSymlinks bool) error {
+	return nil
+}
+
+// Copy the extended attributes (not supported on windows)
+func copyXattrs(src string, dst string, followSymlinks bool) error {
+	return nil
+}
+
+// Give the copy the modification time of the original, the times of a symlink can't be set
+func copyTimes(src string, dst string, info os.FileInfo, followSymlinks bool) error {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
@@ -1,9 +1,10 @@
 package internal
 
 var (
-	HotkeysTomlString  string
-	ConfigTomlString   string
-	DefaultThemeString string
+	HotkeysTomlString    string
+	ConfigTomlString     string
+	DefaultThemeString   string
+	WorkspacesTomlString string
 )
 
 func defaultModelConfig(toggleDotFileBool bool, firstFilePanelDir string) model {
@@ -34,7 +35,8 @@ func defaultModelConfig(toggleDotFileBool bool, firstFilePanelDir string) model
 			filePreview: filePreviewPanel{
 				open: Config.DefaultOpenFilePreview,
 			},
-			width: 10,
+			ratios:        loadLayoutRatios(),
+			millerColumns: Config.DefaultMillerColumns,
 		},
 		helpMenu: helpMenuModal{
 			renderIndex: 0,
@@ -99,6 +101,26 @@ func getHelpMenuData() []helpMenuModalData {
 			description:    "Toggle file preview panel",
 			hotkeyWorkType: globalType,
 		},
+		{
+			hotkey:         hotkeys.ToggleMillerColumns,
+			description:    "Toggle the miller columns layout",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.WidenFilePanel,
+			description:    "Widen the focused file panel",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.NarrowFilePanel,
+			description:    "Narrow the focused file panel",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.ResetLayout,
+			description:    "Reset the file panel widths",
+			hotkeyWorkType: globalType,
+		},
 		{
 			hotkey:         hotkeys.NextFilePanel,
 			description:    "Focus on the next file panel",
@@ -162,16 +184,111 @@ func getHelpMenuData() []helpMenuModalData {
 			description:    "Toggle dot file display",
 			hotkeyWorkType: globalType,
 		},
+		{
+			hotkey:         hotkeys.NextSortType,
+			description:    "Change the sort type of the current directory",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.ReverseSortOrder,
+			description:    "Reverse the sort order of the current directory",
+			hotkeyWorkType: globalType,
+		},
 		{
 			hotkey:         hotkeys.SearchBar,
 			description:    "Toggle active search bar",
 			hotkeyWorkType: globalType,
 		},
+		{
+			hotkey:         hotkeys.RecursiveSearch,
+			description:    "Search in all subdirectories",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.ContentSearch,
+			description:    "Search the content of files in all subdirectories",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.FilterBar,
+			description:    "Filter the directory with a glob or regex",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.HistoryBack,
+			description:    "Go back to the previous directory",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.HistoryForward,
+			description:    "Go forward to the next directory",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenHistory,
+			description:    "Open the directory history of the file panel",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenJump,
+			description:    "Jump to a frequently visited directory",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.GoToPath,
+			description:    "Go to a path (tab to complete)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.TypeAheadFind,
+			description:    "Type the start of a name to move the cursor to it",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.SetMark,
+			description:    "Mark the location with the next letter (uppercase marks are saved)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.JumpToMark,
+			description:    "Jump to the mark of the next letter",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenMarks,
+			description:    "Open the list of marks",
+			hotkeyWorkType: globalType,
+		},
 		{
 			hotkey:         hotkeys.ChangePanelMode,
 			description:    "Change between selection mode or normal mode",
 			hotkeyWorkType: globalType,
 		},
+		{
+			hotkey:         hotkeys.ToggleDetailsView,
+			description:    "Toggle details view (size, date, permissions...)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.ToggleTreeView,
+			description:    "Toggle tree view (expand directories in place)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.ToggleFlatten,
+			description:    "Toggle flattened view (list every file under the directory)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenDiskUsage,
+			description:    "Open disk usage analyzer (press again inside to rescan)",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenWorkspaces,
+			description:    "Open the workspace picker",
+			hotkeyWorkType: globalType,
+		},
 		{
 			hotkey:         hotkeys.PinnedDirectory,
 			description:    "Pin or Unpin folder to sidebar (can be auto saved)",
@@ -210,6 +327,21 @@ func getHelpMenuData() []helpMenuModalData {
 			description:    "Delete selected items",
 			hotkeyWorkType: globalType,
 		},
+		{
+			hotkey:         hotkeys.Undo,
+			description:    "Undo the last rename, create, move or trash",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.Redo,
+			description:    "Redo the last undone file operation",
+			hotkeyWorkType: globalType,
+		},
+		{
+			hotkey:         hotkeys.OpenJournal,
+			description:    "Open the journal of file operations",
+			hotkeyWorkType: globalType,
+		},
 		{
 			hotkey:         hotkeys.ExtractFile,
 			description:    "Extract compressed file",
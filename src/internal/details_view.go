@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+)
+
+// Columns that can be used in details_view_format
+const (
+	detailsColumnName        = "name"
+	detailsColumnSize        = "size"
+	detailsColumnModifyTime  = "mtime"
+	detailsColumnPermissions = "permissions"
+	detailsColumnOwner       = "owner"
+	detailsColumnLink        = "link"
+)
+
+// Fixed width of each column, name and link share the rest of the panel width
+var detailsColumnWidth = map[string]int{
+	detailsColumnSize:        11,
+	detailsColumnModifyTime:  16,
+	detailsColumnPermissions: 10,
+	detailsColumnOwner:       17,
+}
+
+// The minimum width of the name column, columns will be hidden from the end to keep it
+const detailsNameMinimumWidth = 12
+
+// Columns of the details view, parsed from details_view_format
+var detailsColumns []string
+
+// Parse the details view format string e.g. "name,size,mtime,permissions,owner,link"
+func parseDetailsViewFormat(format string) ([]string, error) {
+	columns := []string{}
+	hasName := false
+	for _, column := range strings.Split(format, ",") {
+		column = strings.TrimSpace(column)
+		switch column {
+		case "":
+			continue
+		case detailsColumnName:
+			hasName = true
+		case detailsColumnSize, detailsColumnModifyTime, detailsColumnPermissions, detailsColumnOwner, detailsColumnLink:
+		default:
+			return nil, fmt.Errorf("unknown details view column \"%s\"", column)
+		}
+		if arrayContains(columns, column) {
+			return nil, fmt.Errorf("duplicate details view column \"%s\"", column)
+		}
+		columns = append(columns, column)
+	}
+
+	if !hasName {
+		columns = append([]string{detailsColumnName}, columns...)
+	}
+	return columns, nil
+}
+
+// Return the columns that fit in the panel width and the width of name and link column
+func fitDetailsColumns(columns []string, width int) (fitColumns []string, nameWidth int, linkWidth int) {
+	fitColumns = columns
+	for {
+		fixedWidth := 0
+		hasLink := false
+		for _, column := range fitColumns {
+			if column == detailsColumnLink {
+				hasLink = true
+			} else if column != detailsColumnName {
+				fixedWidth += detailsColumnWidth[column]
+			}
+			if column != detailsColumnName {
+				fixedWidth++
+			}
+		}
+
+		nameWidth = width - fixedWidth
+		linkWidth = 0
+		if hasLink {
+			linkWidth = nameWidth / 3
+			nameWidth -= linkWidth
+		}
+
+		if nameWidth >= detailsNameMinimumWidth || len(fitColumns) == 1 {
+			return fitColumns, nameWidth, linkWidth
+		}
+
+		// Drop the last column that isn't name
+		for i := len(fitColumns) - 1; i >= 0; i-- {
+			if fitColumns[i] != detailsColumnName {
+				fitColumns = append(append([]string{}, fitColumns[:i]...), fitColumns[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Return the value of a details view column for the element
+func detailsColumnValue(column string, item element) string {
+	info := item.info
+	if info == nil {
+		var err error
+		info, err = os.Lstat(item.location)
+		if err != nil {
+			return ""
+		}
+	}
+
+	switch column {
+	case detailsColumnSize:
+		if info.IsDir() {
+			return "-"
+		}
+		return formatFileSize(info.Size())
+	case detailsColumnModifyTime:
+		return info.ModTime().Format("2006-01-02 15:04")
+	case detailsColumnPermissions:
+		return info.Mode().String()
+	case detailsColumnOwner:
+		owner, group := fileOwner(info)
+		if owner == "" {
+			return "-"
+		}
+		return owner + ":" + group
+	case detailsColumnLink:
+		if info.Mode()&os.ModeSymlink == 0 {
+			return ""
+		}
+		target, err := os.Readlink(item.location)
+		if err != nil {
+			return "→ ?"
+		}
+		return "→ " + target
+	}
+	return ""
+}
+
+// Render one element of the file panel in details view
+func detailsViewRender(item element, width int, isSelected bool) string {
+	columns, nameWidth, linkWidth := fitDetailsColumns(detailsColumns, width)
+
+	result := ""
+	for _, column := range columns {
+		if column == detailsColumnName {
+			name := prettierName(item.name, nameWidth-2, item.directory, isSelected, filePanelBGColor)
+			if padding := nameWidth - ansi.StringWidth(name); padding > 0 {
+				name += filePanelStyle.Render(strings.Repeat(" ", padding))
+			}
+			result += name
+			continue
+		}
+
+		columnWidth := linkWidth
+		if column != detailsColumnLink {
+			columnWidth = detailsColumnWidth[column]
+		}
+
+		value := detailsColumnValue(column, item)
+		if ansi.StringWidth(value) > columnWidth {
+			value = truncateText(value, columnWidth, "...")
+		}
+		if column == detailsColumnSize {
+			result += filePanelStyle.Render(fmt.Sprintf(" %*s", columnWidth, value))
+		} else {
+			result += filePanelStyle.Render(fmt.Sprintf(" %-*s", columnWidth, value))
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDetailsViewFormat(t *testing.T) {
+	var inputs = []struct {
+		format   string
+		expected []string
+		hasError bool
+	}{
+		{"name,size,mtime", []string{"name", "size", "mtime"}, false},
+		{"size, owner ,name", []string{"size", "owner", "name"}, false},
+		{"mtime,link", []string{"name", "mtime", "link"}, false},
+		{"", []string{"name"}, false},
+		{"name,colour", nil, true},
+		{"size,size", nil, true},
+	}
+
+	for _, tt := range inputs {
+		t.Run("Parse "+tt.format, func(t *testing.T) {
+			result, err := parseDetailsViewFormat(tt.format)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("got error %v, expected error %v", err, tt.hasError)
+			}
+			if !tt.hasError && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFitDetailsColumns(t *testing.T) {
+	columns := []string{"name", "size", "mtime", "permissions"}
+
+	fitColumns, nameWidth, _ := fitDetailsColumns(columns, 100)
+	if len(fitColumns) != 4 || nameWidth != 100-11-16-10-3 {
+		t.Errorf("got %v with name width %d, expected all columns", fitColumns, nameWidth)
+	}
+
+	fitColumns, nameWidth, _ = fitDetailsColumns(columns, 40)
+	if !reflect.DeepEqual(fitColumns, []string{"name", "size"}) || nameWidth < detailsNameMinimumWidth {
+		t.Errorf("got %v with name width %d, expected name and size", fitColumns, nameWidth)
+	}
+}
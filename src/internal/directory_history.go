@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// Locations remembered by the history of each file panel
+const directoryHistoryMaxLength = 100
+
+// Add the location of every file panel to its history when it changed, otherwise remember the
+// cursor of the current entry so going back to it restores the cursor we left it with
+func (m *model) recordDirectoryHistory() {
+	for i := range m.fileModel.filePanels {
+		panel := &m.fileModel.filePanels[i]
+		if len(panel.history) > 0 && panel.history[panel.historyIndex].location == panel.location {
+			panel.history[panel.historyIndex].cursor = panel.cursor
+			panel.history[panel.historyIndex].render = panel.render
+			continue
+		}
+
+		// A new location drops the locations we went back from
+		if len(panel.history) > 0 {
+			panel.history = panel.history[:panel.historyIndex+1]
+		}
+		panel.history = append(panel.history, directoryHistoryEntry{
+			location: panel.location,
+			cursor:   panel.cursor,
+			render:   panel.render,
+		})
+		if len(panel.history) > directoryHistoryMaxLength {
+			panel.history = panel.history[len(panel.history)-directoryHistoryMaxLength:]
+		}
+		panel.historyIndex = len(panel.history) - 1
+	}
+}
+
+// Go to the entry of the focused file panel history and restore its cursor
+func (m *model) goToHistoryEntry(index int) {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	entry := panel.history[index]
+	panel.directoryRecord[panel.location] = directoryRecord{
+		directoryCursor: panel.cursor,
+		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
+	}
+	panel.historyIndex = index
+	panel.location = entry.location
+	panel.cursor = entry.cursor
+	panel.render = entry.render
+	panel.searchBar.SetValue("")
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Go back to the previous location of the focused file panel, skipping deleted directories
+func (m *model) historyBack() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	for i := panel.historyIndex - 1; i >= 0; i-- {
+		if _, err := os.Stat(panel.history[i].location); err == nil {
+			m.goToHistoryEntry(i)
+			return
+		}
+	}
+}
+
+// Go forward to the location we went back from, skipping deleted directories
+func (m *model) historyForward() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	for i := panel.historyIndex + 1; i < len(panel.history); i++ {
+		if _, err := os.Stat(panel.history[i].location); err == nil {
+			m.goToHistoryEntry(i)
+			return
+		}
+	}
+}
+
+// Open the history popup of the focused file panel, the most recent location is listed first
+func (m *model) openHistoryModal() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	m.historyModal = historyModal{
+		open:   true,
+		cursor: len(panel.history) - 1 - panel.historyIndex,
+	}
+	m.historyModal.renderIndex = max(0, m.historyModal.cursor-historyModalListHeight(m.helpMenu.height)+1)
+}
+
+func (m *model) closeHistoryModal() {
+	m.historyModal = historyModal{}
+}
+
+// Go to the location selected in the history popup
+func (m *model) confirmHistoryModal() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	index := len(panel.history) - 1 - m.historyModal.cursor
+	m.closeHistoryModal()
+	if index < 0 || index >= len(panel.history) {
+		return
+	}
+	m.goToHistoryEntry(index)
+}
+
+// History modal list up
+func (m *model) historyModalListUp() {
+	length := len(m.fileModel.filePanels[m.filePanelFocusIndex].history)
+	if m.historyModal.cursor > 0 {
+		m.historyModal.cursor--
+		if m.historyModal.cursor < m.historyModal.renderIndex {
+			m.historyModal.renderIndex--
+		}
+	} else if length > 0 {
+		m.historyModal.cursor = length - 1
+		m.historyModal.renderIndex = max(0, length-historyModalListHeight(m.helpMenu.height))
+	}
+}
+
+// History modal list down
+func (m *model) historyModalListDown() {
+	length := len(m.fileModel.filePanels[m.filePanelFocusIndex].history)
+	if m.historyModal.cursor < length-1 {
+		m.historyModal.cursor++
+		if m.historyModal.cursor >= m.historyModal.renderIndex+historyModalListHeight(m.helpMenu.height) {
+			m.historyModal.renderIndex++
+		}
+	} else {
+		m.historyModal.cursor = 0
+		m.historyModal.renderIndex = 0
+	}
+}
+
+// Lines of the history modal left for the locations
+func historyModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the history popup
+func (m model) historyModalRender() string {
+	width := m.helpMenu.width
+	history := m.fileModel.filePanels[m.filePanelFocusIndex].history
+	currentIndex := m.fileModel.filePanels[m.filePanelFocusIndex].historyIndex
+
+	content := " " + filePanelTopDirectoryIconStyle.Render(icon.Directory+icon.Space) + filePanelTopPathStyle.Render("History") + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	for i := m.historyModal.renderIndex; i < m.historyModal.renderIndex+historyModalListHeight(m.helpMenu.height) && i < len(history); i++ {
+		if i != m.historyModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.historyModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		index := len(history) - 1 - i
+		location := history[index].location
+		if ansi.StringWidth(location) > width-4 {
+			location = truncateTextBeginning(location, width-4, "...")
+		}
+		if index == currentIndex {
+			content += cursor + helpMenuHotkeyStyle.Render("* "+location)
+		} else {
+			content += cursor + modalStyle.Render("  "+location)
+		}
+	}
+
+	count := "0/0"
+	if len(history) > 0 {
+		count = fmt.Sprintf("%d/%d", m.historyModal.cursor+1, len(history))
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectoryHistory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: dir, directoryRecord: map[string]directoryRecord{}}}}}
+	visit := func(name string, cursor int) {
+		m.fileModel.filePanels[0].location = filepath.Join(dir, name)
+		m.fileModel.filePanels[0].cursor = 0
+		m.recordDirectoryHistory()
+		m.fileModel.filePanels[0].cursor = cursor
+		m.recordDirectoryHistory()
+	}
+	m.recordDirectoryHistory()
+	visit("a", 3)
+	visit("b", 5)
+
+	m.historyBack()
+	panel := m.fileModel.filePanels[0]
+	if panel.location != filepath.Join(dir, "a") || panel.cursor != 3 {
+		t.Fatalf("back should restore a with cursor 3, got %s with cursor %d", panel.location, panel.cursor)
+	}
+	m.recordDirectoryHistory()
+
+	m.historyForward()
+	panel = m.fileModel.filePanels[0]
+	if panel.location != filepath.Join(dir, "b") || panel.cursor != 5 {
+		t.Fatalf("forward should restore b with cursor 5, got %s with cursor %d", panel.location, panel.cursor)
+	}
+	m.recordDirectoryHistory()
+
+	// Visiting a new location after going back drops the forward entries
+	m.historyBack()
+	m.recordDirectoryHistory()
+	visit("c", 1)
+	if len(m.fileModel.filePanels[0].history) != 3 {
+		t.Errorf("history should be the root, a and c: %v", m.fileModel.filePanels[0].history)
+	}
+
+	// Deleted directories are skipped
+	if err := os.Remove(filepath.Join(dir, "a")); err != nil {
+		t.Fatal(err)
+	}
+	m.historyBack()
+	if location := m.fileModel.filePanels[0].location; location != dir {
+		t.Errorf("back should skip the deleted directory, got %s", location)
+	}
+}
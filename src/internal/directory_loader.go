@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lithammer/shortuuid"
+)
+
+// Directories are read this many entries at a time, smaller directories are read at once
+const directoryLoadChunkSize = 1000
+
+// A directory being read in the background
+type directoryLoad struct {
+	id       string
+	location string
+	sort     sortOptions
+	elements []element
+	chunks   chan directoryLoadMsg
+	cancel   context.CancelFunc
+	// The directory is loaded again after it changed, the panel shows the old entries until it's done
+	refresh bool
+}
+
+// Message for a chunk of entries read by a background directory load
+type directoryLoadMsg struct {
+	loadId   string
+	elements []element
+	done     bool
+}
+
+// Read the directory, small directories are returned directly, otherwise a
+// background load is started and its elements are streamed in with directoryLoadMsg
+func loadFolderElement(location string, displayDotFile bool, sortOptions sortOptions) ([]element, *directoryLoad) {
+	directory, err := os.Open(location)
+	if err != nil {
+		outPutLog("Load folder element function open directory error", err)
+		return nil, nil
+	}
+
+	files, err := directory.ReadDir(directoryLoadChunkSize)
+	if err != nil && err != io.EOF {
+		outPutLog("Load folder element function read directory error", err)
+	}
+
+	if len(files) < directoryLoadChunkSize {
+		directory.Close()
+		directoryElement := []element{}
+		for _, item := range files {
+			if newElement, ok := newElementFromDirEntry(location, item, displayDotFile); ok {
+				directoryElement = append(directoryElement, newElement)
+			}
+		}
+		sortElements(directoryElement, sortOptions)
+		return directoryElement, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	load := &directoryLoad{
+		id:       shortuuid.New(),
+		location: location,
+		sort:     sortOptions,
+		chunks:   make(chan directoryLoadMsg, 1),
+		cancel:   cancel,
+	}
+	go load.run(ctx, directory, files, displayDotFile)
+
+	return nil, load
+}
+
+// Read the rest of the directory chunk by chunk until it's done or cancelled
+func (load *directoryLoad) run(ctx context.Context, directory *os.File, files []os.DirEntry, displayDotFile bool) {
+	defer directory.Close()
+	defer close(load.chunks)
+
+	for {
+		chunk := []element{}
+		for _, item := range files {
+			if ctx.Err() != nil {
+				return
+			}
+			if newElement, ok := newElementFromDirEntry(load.location, item, displayDotFile); ok {
+				chunk = append(chunk, newElement)
+			}
+		}
+
+		var err error
+		files, err = directory.ReadDir(directoryLoadChunkSize)
+		if err != nil && err != io.EOF {
+			outPutLog("Directory load read directory error", load.location, err)
+		}
+		done := len(files) == 0
+
+		select {
+		case load.chunks <- directoryLoadMsg{loadId: load.id, elements: chunk, done: done}:
+		case <-ctx.Done():
+			return
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// Wait for the next chunk of the background directory load
+func waitForDirectoryLoad(load *directoryLoad) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-load.chunks
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Add a chunk of a background directory load to its file panel
+func (m *model) handleDirectoryLoad(msg directoryLoadMsg) tea.Cmd {
+	for i := range m.fileModel.filePanels {
+		load := m.fileModel.filePanels[i].directoryLoad
+		if load == nil || load.id != msg.loadId {
+			continue
+		}
+
+		load.elements = append(load.elements, msg.elements...)
+		if !msg.done {
+			return waitForDirectoryLoad(load)
+		}
+
+		sortElements(load.elements, load.sort)
+		panel := &m.fileModel.filePanels[i]
+		panel.element = panel.listingElements(load.elements, m.toggleDotFile)
+		m.fileModel.filePanels[i].directoryLoad = nil
+		m.fileModel.filePanels[i].moveCursorToTargetFile(m.mainPanelHeight)
+		if panel.refreshPending {
+			panel.refreshPending = false
+			panel.lastTimeGetElement = time.Time{}
+		}
+		return nil
+	}
+
+	// The load was cancelled, nobody is waiting for it anymore
+	return nil
+}
+
+// Return whether the file panel has no entries to show until its directory is loaded
+func (panel filePanel) loading() bool {
+	return panel.directoryLoad != nil && !panel.directoryLoad.refresh
+}
+
+// Cancel the background directory load of the file panel
+func (panel *filePanel) cancelDirectoryLoad() {
+	if panel.directoryLoad != nil {
+		panel.directoryLoad.cancel()
+		panel.directoryLoad = nil
+	}
+	panel.refreshPending = false
+}
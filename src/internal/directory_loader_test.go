@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFolderElement(t *testing.T) {
+	smallDir := t.TempDir()
+	for i := 0; i < 10; i++ {
+		os.WriteFile(filepath.Join(smallDir, fmt.Sprintf("file%d", i)), nil, 0644)
+	}
+	os.WriteFile(filepath.Join(smallDir, ".hidden"), nil, 0644)
+
+	elements, load := loadFolderElement(smallDir, false, sortOptions{})
+	if load != nil {
+		t.Fatal("small directory should be loaded directly")
+	}
+	if len(elements) != 10 {
+		t.Errorf("got %d elements, expected 10", len(elements))
+	}
+
+	bigDir := t.TempDir()
+	total := directoryLoadChunkSize*2 + 10
+	for i := 0; i < total; i++ {
+		os.WriteFile(filepath.Join(bigDir, fmt.Sprintf("file%05d", i)), nil, 0644)
+	}
+
+	elements, load = loadFolderElement(bigDir, false, sortOptions{})
+	if load == nil || elements != nil {
+		t.Fatal("big directory should be loaded in the background")
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: bigDir, directoryLoad: load, lastTimeGetElement: time.Now()}}}}
+	cmd := waitForDirectoryLoad(load)
+	for cmd != nil {
+		msg := cmd().(directoryLoadMsg)
+		// A change while loading does not restart the load
+		m.refreshFilePanelsWithLocation(bigDir)
+		if m.fileModel.filePanels[0].lastTimeGetElement.IsZero() {
+			t.Fatal("a change while loading should wait for the load to finish")
+		}
+		cmd = m.handleDirectoryLoad(msg)
+	}
+
+	panel := m.fileModel.filePanels[0]
+	if panel.directoryLoad != nil {
+		t.Fatal("directory load should be done")
+	}
+	if !panel.lastTimeGetElement.IsZero() || panel.refreshPending {
+		t.Error("the change should be loaded once after the load")
+	}
+	if len(panel.element) != total {
+		t.Fatalf("got %d elements, expected %d", len(panel.element), total)
+	}
+	if panel.element[0].name != "file00000" || panel.element[total-1].name != fmt.Sprintf("file%05d", total-1) {
+		t.Errorf("elements are not sorted")
+	}
+}
+
+func TestCancelDirectoryLoad(t *testing.T) {
+	bigDir := t.TempDir()
+	for i := 0; i < directoryLoadChunkSize*3; i++ {
+		os.WriteFile(filepath.Join(bigDir, fmt.Sprintf("file%05d", i)), nil, 0644)
+	}
+
+	_, load := loadFolderElement(bigDir, false, sortOptions{})
+	panel := filePanel{location: bigDir, directoryLoad: load}
+	panel.cancelDirectoryLoad()
+
+	// The chunks channel is closed once the load stops
+	for range load.chunks {
+	}
+	if panel.directoryLoad != nil {
+		t.Error("panel should not be loading anymore")
+	}
+}
+
+func TestRefreshKeepsElementsWhileLoading(t *testing.T) {
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.SearchBar = []string{"/"}
+	bigDir := t.TempDir()
+	total := directoryLoadChunkSize + 10
+	for i := 0; i < total; i++ {
+		os.WriteFile(filepath.Join(bigDir, fmt.Sprintf("file%05d", i)), nil, 0644)
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: bigDir, searchBar: generateSearchBar()}}}}
+	cmd := m.getFilePanelItems()
+	if !m.fileModel.filePanels[0].loading() {
+		t.Fatal("a new directory should be loading without entries")
+	}
+	for load := m.fileModel.filePanels[0].directoryLoad; load != nil; load = m.fileModel.filePanels[0].directoryLoad {
+		msg, ok := <-load.chunks
+		if !ok {
+			t.Fatal("the load stopped before it was done")
+		}
+		m.handleDirectoryLoad(msg)
+	}
+	if len(m.fileModel.filePanels[0].element) != total {
+		t.Fatalf("got %d elements, expected %d", len(m.fileModel.filePanels[0].element), total)
+	}
+
+	os.WriteFile(filepath.Join(bigDir, "new"), nil, 0644)
+	m.refreshFilePanelsWithLocation(bigDir)
+	cmd = m.getFilePanelItems()
+	panel := m.fileModel.filePanels[0]
+	if cmd == nil || panel.directoryLoad == nil || panel.loading() {
+		t.Fatal("the changed directory should be loaded again in the background")
+	}
+	if len(panel.element) != total {
+		t.Errorf("the panel should keep its %d entries while loading again, got %d", total, len(panel.element))
+	}
+	for load := panel.directoryLoad; load != nil; load = m.fileModel.filePanels[0].directoryLoad {
+		msg, ok := <-load.chunks
+		if !ok {
+			t.Fatal("the load stopped before it was done")
+		}
+		m.handleDirectoryLoad(msg)
+	}
+	if len(m.fileModel.filePanels[0].element) != total+1 {
+		t.Errorf("the new file should be shown once loaded, got %d entries", len(m.fileModel.filePanels[0].element))
+	}
+}
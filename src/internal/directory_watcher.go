@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/lithammer/shortuuid"
+)
+
+const (
+	// Wait this long after the last event before telling the model a directory changed
+	watcherDebounceTime = 150 * time.Millisecond
+	// But never delay a change longer than this when events keep coming
+	watcherMaxDelay = time.Second
+	// How often directories on filesystems without inotify support get checked
+	watcherPollingInterval = 2 * time.Second
+)
+
+// Watch the directories open in file panels and notify the model when they change
+type directoryWatcher struct {
+	mutex    sync.Mutex
+	watcher  *fsnotify.Watcher
+	watching map[string]bool
+	polling  map[string]time.Time
+	pending  map[string]bool
+	// When the oldest pending change happened
+	pendingSince time.Time
+	timer        *time.Timer
+}
+
+var dirWatcher *directoryWatcher
+
+// Create the directory watcher, if fsnotify is not available every directory is polled
+func newDirectoryWatcher() *directoryWatcher {
+	w := &directoryWatcher{
+		watching: make(map[string]bool),
+		polling:  make(map[string]time.Time),
+		pending:  make(map[string]bool),
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		outPutLog("Directory watcher create fsnotify watcher error, fall back to polling", err)
+	} else {
+		w.watcher = watcher
+		go w.listenForEvents()
+	}
+
+	go w.pollDirectories()
+	return w
+}
+
+// Make the watched directories the same as the given locations
+func (w *directoryWatcher) sync(locations []string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	wanted := make(map[string]bool)
+	for _, location := range locations {
+		wanted[location] = true
+	}
+
+	for location := range w.watching {
+		if !wanted[location] {
+			w.watcher.Remove(location)
+			delete(w.watching, location)
+		}
+	}
+	for location := range w.polling {
+		if !wanted[location] {
+			delete(w.polling, location)
+		}
+	}
+
+	for location := range wanted {
+		if w.watching[location] {
+			continue
+		}
+		if _, isPolling := w.polling[location]; isPolling {
+			continue
+		}
+
+		if w.watcher != nil && supportsInotify(location) {
+			err := w.watcher.Add(location)
+			if err == nil {
+				w.watching[location] = true
+				continue
+			}
+			outPutLog("Directory watcher add directory error, fall back to polling", location, err)
+		}
+		w.polling[location] = directoryModifyTime(location)
+	}
+}
+
+// Read fsnotify events and collect the changed directories
+func (w *directoryWatcher) listenForEvents() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.mutex.Lock()
+			location := filepath.Dir(event.Name)
+			if w.watching[event.Name] {
+				// The watched directory itself was removed or renamed
+				location = event.Name
+			}
+			w.addPending(location)
+			w.mutex.Unlock()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			outPutLog("Directory watcher error", err)
+		}
+	}
+}
+
+// Check the modify time of directories that can't be watched by fsnotify
+func (w *directoryWatcher) pollDirectories() {
+	ticker := time.NewTicker(watcherPollingInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		w.mutex.Lock()
+		for location, lastModifyTime := range w.polling {
+			modifyTime := directoryModifyTime(location)
+			if !modifyTime.Equal(lastModifyTime) {
+				w.polling[location] = modifyTime
+				w.addPending(location)
+			}
+		}
+		w.mutex.Unlock()
+	}
+}
+
+// Add a changed directory and (re)start the debounce timer, the caller must hold the mutex
+func (w *directoryWatcher) addPending(location string) {
+	if len(w.pending) == 0 {
+		w.pendingSince = time.Now()
+	}
+	w.pending[location] = true
+
+	delay := watcherDebounceTime
+	if remaining := watcherMaxDelay - time.Since(w.pendingSince); remaining < delay {
+		delay = remaining
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(delay, w.flushPending)
+}
+
+// Send all changed directories to the model
+func (w *directoryWatcher) flushPending() {
+	w.mutex.Lock()
+	locations := []string{}
+	for location := range w.pending {
+		locations = append(locations, location)
+	}
+	w.pending = make(map[string]bool)
+	w.mutex.Unlock()
+
+	for _, location := range locations {
+		channel <- channelMessage{
+			messageId:   shortuuid.New(),
+			messageType: sendDirectoryChange,
+			location:    location,
+		}
+	}
+}
+
+// Stop watching all directories
+func (w *directoryWatcher) close() {
+	if w.watcher != nil {
+		w.watcher.Close()
+	}
+}
+
+func directoryModifyTime(location string) time.Time {
+	info, err := os.Stat(location)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
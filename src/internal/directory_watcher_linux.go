@@ -0,0 +1,23 @@
+package internal
+
+import "golang.org/x/sys/unix"
+
+// Filesystems where inotify doesn't see changes made by other machines
+var noInotifyFilesystems = map[int64]bool{
+	0x6969:     true, // nfs
+	0xff534d42: true, // cifs
+	0xfe534d42: true, // smb2
+	0x517b:     true, // smb
+	0x65735546: true, // fuse (sshfs, rclone...)
+	0x01021997: true, // 9p
+	0x00c36400: true, // ceph
+}
+
+// Check whether the directory is on a filesystem that supports inotify
+func supportsInotify(location string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(location, &stat); err != nil {
+		return true
+	}
+	return !noInotifyFilesystems[int64(stat.Type)]
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package internal
+
+// Check whether the directory is on a filesystem that supports native file events
+func supportsInotify(location string) bool {
+	return true
+}
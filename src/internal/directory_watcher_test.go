@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirectoryWatcherNotifyChange(t *testing.T) {
+	dir := t.TempDir()
+	w := newDirectoryWatcher()
+	defer w.close()
+	w.sync([]string{dir})
+
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "file"+string(rune('a'+i))), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-channel:
+			if message.messageType != sendDirectoryChange {
+				continue
+			}
+			if message.location != dir {
+				t.Fatalf("got change of %s, expected %s", message.location, dir)
+			}
+			// The events are debounced, so there must not be a second message
+			select {
+			case message := <-channel:
+				t.Fatalf("got another change of %s", message.location)
+			case <-time.After(500 * time.Millisecond):
+			}
+			return
+		case <-timeout:
+			t.Fatal("no directory change received")
+		}
+	}
+}
@@ -0,0 +1,511 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/lithammer/shortuuid"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+const (
+	// Directories scanned at the same time by a disk usage scan
+	diskUsageWorkers = 8
+	// Send the scan progress to the model this often
+	diskUsageProgressInterval = 100 * time.Millisecond
+	// Width of the percentage bar of each entry
+	diskUsageBarWidth = 20
+)
+
+// Cumulative size of the scanned directories. When a directory is cached every directory
+// under it is cached too, so entering a scanned directory doesn't need another scan
+type diskUsageCache struct {
+	mutex sync.Mutex
+	sizes map[string]int64
+}
+
+var diskUsage = &diskUsageCache{
+	sizes: make(map[string]int64),
+}
+
+// Return the cached size of the directory
+func (c *diskUsageCache) size(location string) (int64, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	size, ok := c.sizes[location]
+	return size, ok
+}
+
+func (c *diskUsageCache) store(location string, size int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sizes[location] = size
+}
+
+// Forget the size of the location and of the directories containing it
+func (c *diskUsageCache) invalidate(location string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for directory := location; ; directory = filepath.Dir(directory) {
+		delete(c.sizes, directory)
+		if filepath.Dir(directory) == directory {
+			break
+		}
+	}
+}
+
+// Forget the location, everything under it and the directories containing it, so it's scanned again
+func (c *diskUsageCache) clear(location string) {
+	c.invalidate(location)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	prefix := strings.TrimSuffix(location, string(os.PathSeparator)) + string(os.PathSeparator)
+	for directory := range c.sizes {
+		if strings.HasPrefix(directory, prefix) {
+			delete(c.sizes, directory)
+		}
+	}
+}
+
+// Remove a deleted entry of the given size from the cache and from the size of the directories containing it
+func (c *diskUsageCache) remove(location string, size int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.sizes, location)
+	prefix := location + string(os.PathSeparator)
+	for directory := range c.sizes {
+		if strings.HasPrefix(directory, prefix) {
+			delete(c.sizes, directory)
+		}
+	}
+	for directory := filepath.Dir(location); ; directory = filepath.Dir(directory) {
+		if cached, ok := c.sizes[directory]; ok {
+			c.sizes[directory] = cached - size
+		}
+		if filepath.Dir(directory) == directory {
+			break
+		}
+	}
+}
+
+// A scan of the size of every directory in a subtree running in the background
+type diskUsageScan struct {
+	id       string
+	scanned  atomic.Int64
+	progress chan diskUsageMsg
+	cancel   context.CancelFunc
+}
+
+// Message with the progress of a disk usage scan
+type diskUsageMsg struct {
+	scanId  string
+	scanned int64
+	done    bool
+}
+
+// Start scanning the subtree of the location, the progress is streamed in with diskUsageMsg
+func startDiskUsageScan(location string) *diskUsageScan {
+	ctx, cancel := context.WithCancel(context.Background())
+	scan := &diskUsageScan{
+		id:       shortuuid.New(),
+		progress: make(chan diskUsageMsg, 1),
+		cancel:   cancel,
+	}
+	go scan.run(ctx, location)
+	return scan
+}
+
+// Scan the subtree with a pool of workers and send the progress until it's done or cancelled
+func (scan *diskUsageScan) run(ctx context.Context, location string) {
+	defer close(scan.progress)
+
+	workers := make(chan struct{}, diskUsageWorkers)
+	finished := make(chan struct{})
+	go func() {
+		scan.scanDirectory(ctx, location, workers)
+		close(finished)
+	}()
+
+	ticker := time.NewTicker(diskUsageProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-finished:
+			if ctx.Err() == nil {
+				scan.progress <- diskUsageMsg{scanId: scan.id, scanned: scan.scanned.Load(), done: true}
+			}
+			return
+		case <-ticker.C:
+			select {
+			case scan.progress <- diskUsageMsg{scanId: scan.id, scanned: scan.scanned.Load()}:
+			default:
+				// The model hasn't read the last progress yet
+			}
+		case <-ctx.Done():
+			<-finished
+			return
+		}
+	}
+}
+
+// Return the cumulative size of the directory and cache it with the size of every directory under it.
+// Subdirectories are scanned by another worker when one is free, otherwise by this one
+func (scan *diskUsageScan) scanDirectory(ctx context.Context, location string, workers chan struct{}) int64 {
+	if size, ok := diskUsage.size(location); ok {
+		return size
+	}
+
+	entries, err := os.ReadDir(location)
+	if err != nil {
+		outPutLog("Disk usage scan read directory error", location, err)
+	}
+
+	var size atomic.Int64
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		scan.scanned.Add(1)
+
+		// Symlinks aren't followed, they count as their own size
+		if entry.IsDir() {
+			subdirectory := filepath.Join(location, entry.Name())
+			select {
+			case workers <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-workers }()
+					size.Add(scan.scanDirectory(ctx, subdirectory, workers))
+				}()
+			default:
+				size.Add(scan.scanDirectory(ctx, subdirectory, workers))
+			}
+			continue
+		}
+
+		if info, err := entry.Info(); err == nil {
+			size.Add(info.Size())
+		}
+	}
+	wg.Wait()
+
+	// The size of a cancelled scan is incomplete
+	if ctx.Err() != nil {
+		return 0
+	}
+	diskUsage.store(location, size.Load())
+	return size.Load()
+}
+
+// Wait for the next progress of the disk usage scan
+func waitForDiskUsageScan(scan *diskUsageScan) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-scan.progress
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Show the progress of the disk usage scan, and the entries once it's done
+func (m *model) handleDiskUsageScan(msg diskUsageMsg) tea.Cmd {
+	scan := m.diskUsageModal.scan
+	if scan == nil || scan.id != msg.scanId {
+		// The scan was cancelled, nobody is waiting for it anymore
+		return nil
+	}
+
+	if msg.done {
+		m.diskUsageModal.scan = nil
+		return m.loadDiskUsageEntries()
+	}
+	return waitForDiskUsageScan(scan)
+}
+
+// Return the entries of the scanned directory sorted by cumulative size
+func readDiskUsageEntries(location string, displayDotFile bool) []diskUsageEntry {
+	files, err := os.ReadDir(location)
+	if err != nil {
+		outPutLog("Disk usage read directory error", location, err)
+	}
+
+	entries := []diskUsageEntry{}
+	for _, item := range files {
+		if !displayDotFile && strings.HasPrefix(item.Name(), ".") {
+			continue
+		}
+
+		entry := diskUsageEntry{
+			name:      item.Name(),
+			location:  filepath.Join(location, item.Name()),
+			directory: item.IsDir(),
+		}
+		if entry.directory {
+			entry.size, _ = diskUsage.size(entry.location)
+		} else if info, err := item.Info(); err == nil {
+			entry.size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].size != entries[j].size {
+			return entries[i].size > entries[j].size
+		}
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// Show the entries of the modal location, or scan it first when it isn't cached
+func (m *model) loadDiskUsageEntries() tea.Cmd {
+	if m.diskUsageModal.scan != nil {
+		m.diskUsageModal.scan.cancel()
+		m.diskUsageModal.scan = nil
+	}
+
+	total, ok := diskUsage.size(m.diskUsageModal.location)
+	if !ok {
+		m.diskUsageModal.entries = nil
+		m.diskUsageModal.scan = startDiskUsageScan(m.diskUsageModal.location)
+		return waitForDiskUsageScan(m.diskUsageModal.scan)
+	}
+
+	m.diskUsageModal.total = total
+	m.diskUsageModal.entries = readDiskUsageEntries(m.diskUsageModal.location, m.toggleDotFile)
+	if m.diskUsageModal.cursor >= len(m.diskUsageModal.entries) {
+		m.diskUsageModal.cursor = max(0, len(m.diskUsageModal.entries)-1)
+	}
+	if m.diskUsageModal.renderIndex > m.diskUsageModal.cursor {
+		m.diskUsageModal.renderIndex = m.diskUsageModal.cursor
+	}
+	return nil
+}
+
+// Open the disk usage analyzer for the current directory
+func (m *model) openDiskUsageModal() tea.Cmd {
+	m.diskUsageModal = diskUsageModal{
+		open:     true,
+		location: m.fileModel.filePanels[m.filePanelFocusIndex].location,
+	}
+	return m.loadDiskUsageEntries()
+}
+
+// Close the disk usage analyzer and stop the scan
+func (m *model) closeDiskUsageModal() {
+	if m.diskUsageModal.scan != nil {
+		m.diskUsageModal.scan.cancel()
+	}
+	m.diskUsageModal = diskUsageModal{}
+}
+
+// Enter the directory under the cursor, it's cached by the scan of its parent
+func (m *model) diskUsageEnterDirectory() tea.Cmd {
+	if m.diskUsageModal.scan != nil || len(m.diskUsageModal.entries) == 0 {
+		return nil
+	}
+	entry := m.diskUsageModal.entries[m.diskUsageModal.cursor]
+	if !entry.directory {
+		return nil
+	}
+
+	m.diskUsageModal.location = entry.location
+	m.diskUsageModal.cursor = 0
+	m.diskUsageModal.renderIndex = 0
+	return m.loadDiskUsageEntries()
+}
+
+// Go to the parent directory and put the cursor on the directory we came from
+func (m *model) diskUsageParentDirectory() tea.Cmd {
+	previous := m.diskUsageModal.location
+	parent := filepath.Dir(previous)
+	if parent == previous {
+		return nil
+	}
+
+	m.diskUsageModal.location = parent
+	m.diskUsageModal.cursor = 0
+	m.diskUsageModal.renderIndex = 0
+	cmd := m.loadDiskUsageEntries()
+	for i, entry := range m.diskUsageModal.entries {
+		if entry.location == previous {
+			m.diskUsageModal.cursor = i
+			m.diskUsageModal.renderIndex = max(0, i-diskUsageModalListHeight(m.helpMenu.height)+1)
+			break
+		}
+	}
+	return cmd
+}
+
+// Scan the current directory of the disk usage analyzer again
+func (m *model) diskUsageRescan() tea.Cmd {
+	diskUsage.clear(m.diskUsageModal.location)
+	return m.loadDiskUsageEntries()
+}
+
+// Ask to confirm deleting the entry under the cursor with the same warning as the file panel
+func (m *model) diskUsageDelete() {
+	if m.diskUsageModal.scan != nil || len(m.diskUsageModal.entries) == 0 {
+		return
+	}
+	m.warnModal = warnModal{
+		open:     true,
+		title:    "Are you sure you want to move this to trash can",
+		content:  "This operation will move file or directory to trash can.",
+		warnType: confirmDeleteItem,
+	}
+	if isExternalDiskPath(m.diskUsageModal.entries[m.diskUsageModal.cursor].location) {
+		m.warnModal.title = "Are you sure you want to completely delete"
+		m.warnModal.content = "This operation cannot be undone and your data will be completely lost."
+	}
+}
+
+// Delete the entry under the cursor like the file panel does, once it was confirmed
+func (m *model) confirmDiskUsageDelete() {
+	if m.diskUsageModal.scan != nil || len(m.diskUsageModal.entries) == 0 {
+		return
+	}
+	entry := m.diskUsageModal.entries[m.diskUsageModal.cursor]
+	diskUsage.remove(entry.location, entry.size)
+	m.diskUsageModal.total -= entry.size
+	m.diskUsageModal.entries = append(m.diskUsageModal.entries[:m.diskUsageModal.cursor:m.diskUsageModal.cursor], m.diskUsageModal.entries[m.diskUsageModal.cursor+1:]...)
+	if m.diskUsageModal.cursor >= len(m.diskUsageModal.entries) {
+		m.diskUsageModal.cursor = max(0, len(m.diskUsageModal.entries)-1)
+	}
+	if m.diskUsageModal.renderIndex > m.diskUsageModal.cursor {
+		m.diskUsageModal.renderIndex = m.diskUsageModal.cursor
+	}
+
+	go func() {
+		var err error
+		if isExternalDiskPath(entry.location) {
+			err = os.RemoveAll(entry.location)
+		} else {
+			var item journalItem
+			item, err = trashMacOrLinux(entry.location)
+			if err == nil {
+				recordJournal(journalTrash, []journalItem{item})
+			}
+		}
+		if err != nil {
+			outPutLog("Disk usage delete entry error", entry.location, err)
+			diskUsage.invalidate(filepath.Dir(entry.location))
+		}
+	}()
+}
+
+// Disk usage modal list up
+func (m *model) diskUsageModalListUp() {
+	if m.diskUsageModal.cursor > 0 {
+		m.diskUsageModal.cursor--
+		if m.diskUsageModal.cursor < m.diskUsageModal.renderIndex {
+			m.diskUsageModal.renderIndex--
+		}
+	} else if len(m.diskUsageModal.entries) > 0 {
+		m.diskUsageModal.cursor = len(m.diskUsageModal.entries) - 1
+		m.diskUsageModal.renderIndex = max(0, len(m.diskUsageModal.entries)-diskUsageModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Disk usage modal list down
+func (m *model) diskUsageModalListDown() {
+	if m.diskUsageModal.cursor < len(m.diskUsageModal.entries)-1 {
+		m.diskUsageModal.cursor++
+		if m.diskUsageModal.cursor >= m.diskUsageModal.renderIndex+diskUsageModalListHeight(m.helpMenu.height) {
+			m.diskUsageModal.renderIndex++
+		}
+	} else {
+		m.diskUsageModal.cursor = 0
+		m.diskUsageModal.renderIndex = 0
+	}
+}
+
+// Lines of the disk usage modal left for the entries
+func diskUsageModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the percentage bar of an entry
+func diskUsageBar(size int64, total int64) string {
+	filled := 0
+	if total > 0 {
+		filled = int(size * diskUsageBarWidth / total)
+	}
+	filled = min(max(filled, 0), diskUsageBarWidth)
+	return filePanelTopDirectoryIconStyle.Render(strings.Repeat("█", filled)) + modalStyle.Render(strings.Repeat("░", diskUsageBarWidth-filled))
+}
+
+// Render the disk usage modal
+func (m model) diskUsageModalRender() string {
+	width := m.helpMenu.width
+	header := icon.Directory + icon.Space + m.diskUsageModal.location
+	if m.diskUsageModal.scan == nil {
+		header += "  " + formatFileSize(m.diskUsageModal.total)
+	}
+	if ansi.StringWidth(header) > width-2 {
+		header = truncateTextBeginning(header, width-2, "...")
+	}
+	content := " " + filePanelTopPathStyle.Render(header) + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	if m.diskUsageModal.scan != nil {
+		content += modalStyle.Render(fmt.Sprintf(" %s  Scanning %d entries...", icon.InOperation, m.diskUsageModal.scan.scanned.Load()))
+	} else if len(m.diskUsageModal.entries) == 0 {
+		content += modalStyle.Render(" " + icon.Error + "  Empty directory")
+	}
+
+	entries := m.diskUsageModal.entries
+	for i := m.diskUsageModal.renderIndex; i < m.diskUsageModal.renderIndex+diskUsageModalListHeight(m.helpMenu.height) && i < len(entries); i++ {
+		if i != m.diskUsageModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.diskUsageModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		percentage := 0.0
+		if m.diskUsageModal.total > 0 {
+			percentage = float64(entries[i].size) * 100 / float64(m.diskUsageModal.total)
+		}
+		usage := fmt.Sprintf("%9s %5.1f%% ", formatFileSize(entries[i].size), percentage)
+		name := entries[i].name
+		if entries[i].directory {
+			name += string(os.PathSeparator)
+		}
+		nameWidth := width - 2 - ansi.StringWidth(usage) - diskUsageBarWidth - 1
+		if ansi.StringWidth(name) > nameWidth {
+			name = truncateText(name, nameWidth, "...")
+		}
+		content += cursor + helpMenuHotkeyStyle.Render(usage) + diskUsageBar(entries[i].size, m.diskUsageModal.total) + modalStyle.Render(" "+name)
+	}
+
+	count := "0/0"
+	if len(entries) > 0 {
+		count = fmt.Sprintf("%d/%d", m.diskUsageModal.cursor+1, len(entries))
+	}
+	if m.diskUsageModal.scan != nil {
+		count += " ..."
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
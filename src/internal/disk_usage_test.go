@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskUsageScan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]int{"a/b/big.bin": 3000, "a/small.txt": 100, "c.txt": 500, "d/e/f/g.txt": 10}
+	for path, size := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan := &diskUsageScan{}
+	workers := make(chan struct{}, diskUsageWorkers)
+	if size := scan.scanDirectory(context.Background(), dir, workers); size != 3610 {
+		t.Fatalf("size of the directory is %d, expected 3610", size)
+	}
+
+	expected := map[string]int64{"a": 3100, "a/b": 3000, "d": 10, "d/e/f": 10}
+	for path, size := range expected {
+		if cached, ok := diskUsage.size(filepath.Join(dir, path)); !ok || cached != size {
+			t.Errorf("cached size of %s is %d (cached %t), expected %d", path, cached, ok, size)
+		}
+	}
+
+	entries := readDiskUsageEntries(dir, false)
+	if len(entries) != 3 || entries[0].name != "a" || entries[1].name != "c.txt" || entries[2].name != "d" {
+		t.Errorf("entries should be sorted by size: %v", entries)
+	}
+
+	diskUsage.remove(filepath.Join(dir, "a/b"), 3000)
+	if size, _ := diskUsage.size(dir); size != 610 {
+		t.Errorf("size after removing a/b is %d, expected 610", size)
+	}
+	if _, ok := diskUsage.size(filepath.Join(dir, "a/b")); ok {
+		t.Error("removed directory should not be cached")
+	}
+
+	diskUsage.invalidate(filepath.Join(dir, "d/e"))
+	for _, path := range []string{dir, filepath.Join(dir, "d"), filepath.Join(dir, "d/e")} {
+		if _, ok := diskUsage.size(path); ok {
+			t.Errorf("%s should be invalidated", path)
+		}
+	}
+	if _, ok := diskUsage.size(filepath.Join(dir, "d/e/f")); !ok {
+		t.Error("directories under the invalidated one should stay cached")
+	}
+}
+
+func TestDiskUsageDeleteAsksToConfirm(t *testing.T) {
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.Quit = []string{"q"}
+	m := model{diskUsageModal: diskUsageModal{
+		open:    true,
+		entries: []diskUsageEntry{{location: "/dir/big.bin", size: 10}},
+		total:   10,
+	}}
+	m.diskUsageDelete()
+	if !m.warnModal.open || m.warnModal.warnType != confirmDeleteItem {
+		t.Fatal("delete should open the delete confirmation of the file panel")
+	}
+
+	m.warnModalOpenKey("q")
+	if m.warnModal.open || len(m.diskUsageModal.entries) != 1 {
+		t.Error("cancelling should keep the entry")
+	}
+}
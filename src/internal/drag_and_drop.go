@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// Files dragged with the mouse from a file panel, dropped on another panel or a directory
+type fileDrag struct {
+	pressed  bool
+	dragging bool
+	panel    int
+	items    []string
+	// Whether the pressed entry is a directory, for the icon of the drag ghost
+	directory bool
+	move      bool
+	x         int
+	y         int
+}
+
+// Dispatch the mouse event to clicks, dragging and dropping files and resizing panels by their borders
+func (m *model) mouseAction(msg tea.MouseEvent) {
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if msg.Button == tea.MouseButtonLeft && m.startBorderDrag(msg.X, msg.Y) {
+			return
+		}
+		m.mouseClickAction(msg)
+		m.startFileDrag(msg)
+	case tea.MouseActionMotion:
+		if m.borderDrag.active {
+			m.moveBorderDrag(msg.X)
+			return
+		}
+		m.moveFileDrag(msg)
+	case tea.MouseActionRelease:
+		if m.borderDrag.active {
+			m.endBorderDrag()
+			return
+		}
+		m.dropFileDrag(msg)
+	}
+}
+
+// Remember the pressed entry, it is dragged once the mouse moves with the button held
+func (m *model) startFileDrag(msg tea.MouseEvent) {
+	m.fileDrag = fileDrag{}
+	if msg.Button != tea.MouseButtonLeft || m.mouseBlocked() {
+		return
+	}
+	target, panelIndex := m.mouseTargetAt(msg.X, msg.Y)
+	if target != filePanelMouseTarget {
+		return
+	}
+	index := m.filePanelElementAt(panelIndex, msg.Y)
+	if index < 0 {
+		return
+	}
+	panel := m.fileModel.filePanels[panelIndex]
+	m.fileDrag = fileDrag{
+		pressed:   true,
+		panel:     panelIndex,
+		items:     dragItems(panel, panel.element[index].location),
+		directory: panel.element[index].directory,
+		x:         msg.X,
+		y:         msg.Y,
+	}
+}
+
+// Return the dragged items: the selection when the pressed entry is part of it, otherwise the entry
+func dragItems(panel filePanel, pressed string) []string {
+	if panel.panelMode == selectMode && arrayContains(panel.selected, pressed) {
+		return append([]string{}, panel.selected...)
+	}
+	return []string{pressed}
+}
+
+// Follow the mouse with the drag ghost
+func (m *model) moveFileDrag(msg tea.MouseEvent) {
+	if !m.fileDrag.pressed || msg.Button != tea.MouseButtonLeft {
+		return
+	}
+	m.fileDrag.dragging = true
+	m.fileDrag.move = msg.Alt
+	m.fileDrag.x = msg.X
+	m.fileDrag.y = msg.Y
+}
+
+// Copy the dragged items into the directory they were dropped on, or move them when alt is held
+func (m *model) dropFileDrag(msg tea.MouseEvent) {
+	drag := m.fileDrag
+	m.fileDrag = fileDrag{}
+	if !drag.dragging || m.mouseBlocked() {
+		return
+	}
+
+	destination := m.dropDestination(drag.panel, msg.X, msg.Y)
+	if destination == "" || !validDropDestination(drag.items, destination) {
+		return
+	}
+	items := copyItems{items: drag.items, cut: drag.move || msg.Alt}
+	// The paste gets a copy of the model, the model keeps changing while the files are pasted
+	go m.pasteItemsTo(items, destination)
+}
+
+// Return the directory under the position: a directory entry of a file panel or the sidebar,
+// otherwise the location of a file panel other than the one the drag started in
+func (m model) dropDestination(sourcePanel int, x int, y int) string {
+	target, panelIndex := m.mouseTargetAt(x, y)
+	switch target {
+	case sidebarMouseTarget:
+		if index := m.sidebarDirectoryAt(y); index >= 0 {
+			return m.sidebarModel.directories[index].location
+		}
+	case filePanelMouseTarget:
+		panel := m.fileModel.filePanels[panelIndex]
+		if index := m.filePanelElementAt(panelIndex, y); index >= 0 && panel.element[index].directory {
+			return panel.element[index].location
+		}
+		if panelIndex != sourcePanel {
+			return panel.location
+		}
+	}
+	return ""
+}
+
+// Return whether the items can be dropped in the directory, they can not be dropped where
+// they already are or into themselves
+func validDropDestination(items []string, destination string) bool {
+	for _, item := range items {
+		if filepath.Dir(item) == destination || isSubPath(item, destination) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return whether the path is the directory or inside it
+func isSubPath(directory string, path string) bool {
+	rel, err := filepath.Rel(directory, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Render the dragged items next to the mouse
+func (m model) fileDragGhostRender() string {
+	action := "Copy "
+	if m.fileDrag.move {
+		action = "Move "
+	}
+	width := min(30, m.fileModel.filePanels[m.fileDrag.panel].width)
+	content := ""
+	if len(m.fileDrag.items) == 1 {
+		name := filepath.Base(m.fileDrag.items[0])
+		style := getElementIcon(name, m.fileDrag.directory)
+		content = stringColorRender(lipgloss.Color(style.Color), modalBGColor).Background(modalBGColor).Render(style.Icon+" ") +
+			modalStyle.Render(truncateText(name, width-10, "..."))
+	} else {
+		content = modalStyle.Render(icon.Directory + icon.Space + strconv.Itoa(len(m.fileDrag.items)) + " items")
+	}
+	return modalBorderStyle(1, width).Align(lipgloss.Left, lipgloss.Center).Render(modalStyle.Render(action) + content)
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFileDrag(t *testing.T) {
+	defer func(saved ConfigType) { Config = saved }(Config)
+	Config.SidebarWidth = 20
+	m := model{
+		fullWidth:       120,
+		mainPanelHeight: 30,
+		fileModel: fileModel{filePanels: []filePanel{
+			{location: "/src", width: 30, focusType: focus, panelMode: selectMode, selected: []string{"/src/a", "/src/c"}, element: []element{
+				{name: "a", location: "/src/a"}, {name: "b", location: "/src/b"}, {name: "c", location: "/src/c", directory: true},
+			}},
+			{location: "/dst", width: 30, element: []element{{name: "d", location: "/dst/d", directory: true}, {name: "e", location: "/dst/e"}}},
+		}},
+	}
+
+	// Pressing a selected entry drags the whole selection
+	m.mouseAction(tea.MouseEvent{X: 25, Y: filePanelElementTop, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if m.fileDrag.dragging || len(m.fileDrag.items) != 2 {
+		t.Fatalf("a press should not drag yet but remember the selection, got %+v", m.fileDrag)
+	}
+	m.mouseAction(tea.MouseEvent{X: 60, Y: 10, Alt: true, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft})
+	if !m.fileDrag.dragging || !m.fileDrag.move {
+		t.Errorf("moving with alt held should drag to move, got %+v", m.fileDrag)
+	}
+
+	if dragged := dragItems(m.fileModel.filePanels[0], "/src/b"); len(dragged) != 1 || dragged[0] != "/src/b" {
+		t.Errorf("an unselected entry should be dragged alone, got %v", dragged)
+	}
+
+	if destination := m.dropDestination(0, 60, filePanelElementTop); destination != "/dst/d" {
+		t.Errorf("dropping on a directory should drop into it, got %q", destination)
+	}
+	if destination := m.dropDestination(0, 60, filePanelElementTop+1); destination != "/dst" {
+		t.Errorf("dropping on a file of another panel should drop into the panel, got %q", destination)
+	}
+	if destination := m.dropDestination(0, 25, filePanelElementTop+1); destination != "" {
+		t.Errorf("dropping on a file of the same panel should do nothing, got %q", destination)
+	}
+
+	if validDropDestination([]string{"/src/a"}, "/src") {
+		t.Error("items should not be dropped where they already are")
+	}
+	if validDropDestination([]string{"/src/c"}, "/src/c/inner") {
+		t.Error("a directory should not be dropped into itself")
+	}
+	if !validDropDestination([]string{"/src/c"}, "/src/cc") {
+		t.Error("a sibling directory sharing the prefix should be valid")
+	}
+}
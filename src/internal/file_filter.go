@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+)
+
+// The filter shown in the bottom border of the file panel is truncated to this width
+const filterIndicatorMaxWidth = 16
+
+// Compile the filter of a file panel, patterns starting with "re:" are regular expressions,
+// other patterns are globs matched against the whole name, e.g. "*.go". A glob without
+// wildcards matches every name that contains it. Globs are case insensitive when they
+// are all lowercase
+func compileFilterPattern(pattern string) (func(name string) bool, error) {
+	if regexPattern, isRegex := strings.CutPrefix(pattern, "re:"); isRegex {
+		regex, err := regexp.Compile(regexPattern)
+		if err != nil {
+			return nil, err
+		}
+		return regex.MatchString, nil
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		pattern = "*" + pattern + "*"
+	}
+	ignoreCase := strings.ToLower(pattern) == pattern
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	return func(name string) bool {
+		if ignoreCase {
+			name = strings.ToLower(name)
+		}
+		matched, _ := filepath.Match(pattern, name)
+		return matched
+	}, nil
+}
+
+// Return the filter of the file panel, nil when the panel isn't filtered or the filter is invalid
+func (panel filePanel) filterMatcher() func(name string) bool {
+	if panel.searchMode != filterSearchMode || panel.searchBar.Value() == "" {
+		return nil
+	}
+	match, err := compileFilterPattern(panel.searchBar.Value())
+	if err != nil {
+		return nil
+	}
+	return match
+}
+
+// Return the elements whose name matches the filter, keeping their order. Only the base
+// name is matched, so "*.log" matches at any depth of a flattened listing
+func filterElements(elements []element, match func(name string) bool) []element {
+	if match == nil {
+		return elements
+	}
+
+	filtered := []element{}
+	for _, item := range elements {
+		if match(filepath.Base(item.name)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Return the filter indicator shown in the bottom border of the file panel
+func (panel filePanel) filterIndicator() string {
+	if panel.searchMode != filterSearchMode || panel.searchBar.Value() == "" {
+		return ""
+	}
+
+	pattern := panel.searchBar.Value()
+	if ansi.StringWidth(pattern) > filterIndicatorMaxWidth {
+		pattern = truncateText(pattern, filterIndicatorMaxWidth, "...")
+	}
+	indicator := "Filter: " + pattern
+	if _, err := compileFilterPattern(panel.searchBar.Value()); err != nil {
+		indicator += " (invalid)"
+	}
+	return indicator
+}
+
+// Focus on the search bar to filter the directory while keeping the sort order
+func (m *model) filterBarFocus() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if panel.searchBar.Focused() && panel.searchMode == filterSearchMode {
+		panel.searchBar.Blur()
+	} else {
+		if panel.searchMode != filterSearchMode {
+			panel.searchBar.SetValue("")
+		}
+		panel.searchMode = filterSearchMode
+		panel.searchBar.Placeholder = searchBarPlaceholder(filterSearchMode)
+		panel.searchBar.Focus()
+	}
+
+	// config search bar width
+	panel.searchBar.Width = panel.width - 4
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
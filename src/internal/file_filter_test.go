@@ -0,0 +1,56 @@
+package internal
+
+import "testing"
+
+func TestCompileFilterPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "main.go.bak", false},
+		{"*.go", "MAIN.GO", true},
+		{"*.GO", "main.go", false},
+		{"test_?.txt", "test_1.txt", true},
+		{"readme", "README.md", true},
+		{"re:^test_", "test_model.go", true},
+		{"re:^test_", "model_test.go", false},
+	}
+
+	for _, tc := range testCases {
+		match, err := compileFilterPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("compile %q: %v", tc.pattern, err)
+		}
+		if match(tc.name) != tc.match {
+			t.Errorf("filter %q on %q: expected match %t", tc.pattern, tc.name, tc.match)
+		}
+	}
+
+	for _, pattern := range []string{"re:(", "[a-"} {
+		if _, err := compileFilterPattern(pattern); err == nil {
+			t.Errorf("invalid filter %q should return an error", pattern)
+		}
+	}
+}
+
+func TestFilterElementsKeepsOrder(t *testing.T) {
+	elements := []element{{name: "b.go"}, {name: "a.txt"}, {name: "c.go"}, {name: "a.go"}}
+	match, _ := compileFilterPattern("*.go")
+
+	filtered := filterElements(elements, match)
+	expected := []string{"b.go", "c.go", "a.go"}
+	if len(filtered) != len(expected) {
+		t.Fatalf("got %d elements, expected %d", len(filtered), len(expected))
+	}
+	for i, name := range expected {
+		if filtered[i].name != name {
+			t.Errorf("element %d is %q, expected %q", i, filtered[i].name, name)
+		}
+	}
+
+	if len(filterElements(elements, nil)) != len(elements) {
+		t.Error("no filter should keep every element")
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"time"
 
+	"github.com/rkoesters/xdg/basedir"
 	"github.com/rkoesters/xdg/trash"
 	varibale "github.com/yorukot/superfile/src/config"
 	"github.com/yorukot/superfile/src/config/icon"
@@ -20,20 +23,74 @@ func moveElement(src, dst string) error {
 	return nil
 }
 
-// Move file to trash can and can auto switch macos trash can or linux trash can
-func trashMacOrLinux(src string) error {
+// Move file to trash can and can auto switch macos trash can or linux trash can, returns where the
+// file went for the journal
+func trashMacOrLinux(src string) (journalItem, error) {
 	if runtime.GOOS == "darwin" {
-		err := moveElement(src, varibale.HomeDir+"/.Trash/"+filepath.Base(src))
+		dst := varibale.HomeDir + "/.Trash/" + filepath.Base(src)
+		err := moveElement(src, dst)
 		if err != nil {
 			outPutLog("Delete single item function move file to trash can error", err)
+			return journalItem{}, err
 		}
-	} else {
-		err := trash.Trash(src)
+		return newJournalItem(src, dst), nil
+	}
+
+	// Reserve the name in the trash can by creating its trashinfo file, like the trash spec asks, so
+	// the journal knows where the file went even when something else trashes a file with the same name
+	trashDir := filepath.Join(basedir.DataHome, "Trash")
+	absPath, err := filepath.Abs(src)
+	if err != nil {
+		return journalItem{}, err
+	}
+	info := trash.Info{Path: absPath, DeletionDate: time.Now()}
+	name := filepath.Base(src)
+	for i := 2; ; i++ {
+		infoPath := filepath.Join(trashDir, "info", name+".trashinfo")
+		trashPath := filepath.Join(trashDir, "files", name)
+		reserved, err := reserveTrashName(infoPath, trashPath, info)
 		if err != nil {
 			outPutLog("Paste item function move file to trash can error", err)
+			return journalItem{}, err
 		}
+		if reserved {
+			err = os.Rename(src, trashPath)
+			if err != nil {
+				os.Remove(infoPath)
+				outPutLog("Paste item function move file to trash can error", err)
+				return journalItem{}, err
+			}
+			item := newJournalItem(src, trashPath)
+			item.TrashInfo = infoPath
+			return item, nil
+		}
+		name = filepath.Base(src) + "." + strconv.Itoa(i)
 	}
-	return nil
+}
+
+// Write the trashinfo file if no file in the trash can has the name yet, returns false if it has
+func reserveTrashName(infoPath string, trashPath string, info trash.Info) (bool, error) {
+	file, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = file.WriteString(info.String())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// A file without trashinfo keeps its name too, renaming over it would lose it
+		if _, statErr := os.Lstat(trashPath); statErr == nil {
+			os.Remove(infoPath)
+			return false, nil
+		}
+		return true, nil
+	}
+	os.Remove(infoPath)
+	return false, err
 }
 
 // Paste all item in directory
@@ -44,66 +101,38 @@ func pasteDir(src, dst string, id string, m model) (model, error) {
 		return m, err
 	}
 
-	err = filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// Copy like cp -a, with the attributes and symlinks the config keeps
+	err = copyTree(src, dst, configCopyOptions(), func(path string) error {
+		p := m.processBarModel.process[id]
 
-		relPath, err := filepath.Rel(src, path)
-		if err != nil {
-			return err
+		message := channelMessage{
+			messageId:       id,
+			messageType:     sendProcess,
+			processNewState: p,
 		}
 
-		newPath := filepath.Join(dst, relPath)
-		
-		if info.IsDir() {
-			newPath, err = renameIfDuplicate(newPath)
-			if err != nil {
-				return err
-			}
-			err = os.MkdirAll(newPath, info.Mode())
-			if err != nil {
-				return err
-			}
+		if m.copyItems.cut {
+			p.name = icon.Cut + icon.Space + filepath.Base(path)
 		} else {
-			p := m.processBarModel.process[id]
-
-			message := channelMessage{
-				messageId: id,
-				messageType: sendProcess,
-				processNewState: p,
-			}
-
-			if m.copyItems.cut {
-				p.name = icon.Cut + icon.Space + filepath.Base(path)
-			} else {
-				p.name = icon.Copy + icon.Space + filepath.Base(path)
-			}
-
-			if len(channel) < 5 {
-				message.processNewState = p
-				channel <- message
-			}
-
-			err := pasteFile(path, newPath)
-			if err != nil {
-				p.state = failure
-				message.processNewState = p
-				channel <- message
-				return err
-			}
-			p.done++
-			if len(channel) < 5 {
-				message.processNewState = p
-				channel <- message
-			}
-			m.processBarModel.process[id] = p
+			p.name = icon.Copy + icon.Space + filepath.Base(path)
 		}
-
+		p.done++
+		if len(channel) < 5 {
+			message.processNewState = p
+			channel <- message
+		}
+		m.processBarModel.process[id] = p
 		return nil
 	})
 
 	if err != nil {
+		p := m.processBarModel.process[id]
+		p.state = failure
+		channel <- channelMessage{
+			messageId:       id,
+			messageType:     sendProcess,
+			processNewState: p,
+		}
 		return m, err
 	}
 
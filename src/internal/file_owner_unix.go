@@ -0,0 +1,45 @@
+//go:build !windows
+
+package internal
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// Cache of user and group names, looking them up on every render is slow
+var ownerNameCache = map[string]string{}
+var groupNameCache = map[string]string{}
+
+// Return the owner and group name of the file
+func fileOwner(info os.FileInfo) (owner string, group string) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", ""
+	}
+
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	gid := strconv.FormatUint(uint64(stat.Gid), 10)
+
+	owner, hasOwner := ownerNameCache[uid]
+	if !hasOwner {
+		owner = uid
+		if u, err := user.LookupId(uid); err == nil {
+			owner = u.Username
+		}
+		ownerNameCache[uid] = owner
+	}
+
+	group, hasGroup := groupNameCache[gid]
+	if !hasGroup {
+		group = gid
+		if g, err := user.LookupGroupId(gid); err == nil {
+			group = g.Name
+		}
+		groupNameCache[gid] = group
+	}
+
+	return owner, group
+}
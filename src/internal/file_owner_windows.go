@@ -0,0 +1,10 @@
+//go:build windows
+
+package internal
+
+import "os"
+
+// Return the owner and group name of the file (not supported on windows)
+func fileOwner(info os.FileInfo) (owner string, group string) {
+	return "", ""
+}
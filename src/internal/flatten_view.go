@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Flattened listings stop after this many entries
+	flattenMaxEntries = 10000
+	// Only the first directories of a flattened listing are watched for changes
+	flattenMaxWatchedDirectories = 256
+)
+
+// Return every entry under the directory up to maxDepth levels deep, each directory is
+// followed by its own entries sorted with its sort options. Names are relative to the location
+func flattenFolderElement(location string, displayDotFile bool, maxDepth int) []element {
+	result := []element{}
+	flattenFolderLevel(location, "", displayDotFile, maxDepth, &result)
+	return result
+}
+
+func flattenFolderLevel(location string, relativePath string, displayDotFile bool, depth int, result *[]element) {
+	for _, item := range returnFolderElement(location, displayDotFile, getSortOptions(location)) {
+		if len(*result) >= flattenMaxEntries {
+			return
+		}
+		// Symlinked directories aren't followed, so the walk can't loop
+		name := item.name
+		if relativePath != "" {
+			name = filepath.Join(relativePath, item.name)
+		}
+		item.name = name
+		*result = append(*result, item)
+
+		if item.directory && depth > 1 {
+			flattenFolderLevel(item.location, name, displayDotFile, depth-1, result)
+		}
+	}
+}
+
+// Return the directories of the flattened listing that should be watched for changes
+func (panel filePanel) visibleFlattenedDirectories() []string {
+	if !panel.flatten {
+		return nil
+	}
+
+	directories := []string{}
+	for _, item := range panel.element {
+		if len(directories) >= flattenMaxWatchedDirectories {
+			break
+		}
+		if item.directory {
+			directories = append(directories, item.location)
+		}
+	}
+	return directories
+}
+
+// Return whether the location is listed by the flattened file panel
+func (panel filePanel) flattenContains(location string) bool {
+	if !panel.flatten {
+		return false
+	}
+	relativePath, err := filepath.Rel(panel.location, location)
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(os.PathSeparator))
+}
+
+// List every entry under the directory of the file panel in one list, or go back to the normal listing
+func (m *model) toggleFlattenView() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	// Keep the cursor on the same entry, or on the directory containing it
+	if len(panel.element) > 0 {
+		panel.targetFile = panel.element[panel.cursor].location
+		if panel.flatten {
+			topName, _, _ := strings.Cut(panel.element[panel.cursor].name, string(os.PathSeparator))
+			panel.targetFile = filepath.Join(panel.location, topName)
+		}
+	}
+	panel.flatten = !panel.flatten
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
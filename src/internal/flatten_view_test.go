@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlattenFolderElement(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"a/b/c/deep.log", "a/x.log", ".hidden/y.log", "z.txt"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(elements []element) []string {
+		result := []string{}
+		for _, item := range elements {
+			result = append(result, filepath.ToSlash(item.name))
+		}
+		return result
+	}
+	testCases := []struct {
+		displayDotFile bool
+		maxDepth       int
+		expected       []string
+	}{
+		{false, 1, []string{"a", "z.txt"}},
+		{false, 3, []string{"a", "a/b", "a/b/c", "a/x.log", "z.txt"}},
+		{true, 2, []string{".hidden", ".hidden/y.log", "a", "a/b", "a/x.log", "z.txt"}},
+	}
+	for _, tc := range testCases {
+		got := names(flattenFolderElement(dir, tc.displayDotFile, tc.maxDepth))
+		if len(got) != len(tc.expected) {
+			t.Errorf("depth %d dotfile %t: got %v, expected %v", tc.maxDepth, tc.displayDotFile, got, tc.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.expected[i] {
+				t.Errorf("depth %d dotfile %t: got %v, expected %v", tc.maxDepth, tc.displayDotFile, got, tc.expected)
+				break
+			}
+		}
+	}
+
+	match, _ := compileFilterPattern("*.log")
+	filtered := names(filterElements(flattenFolderElement(dir, false, 5), match))
+	if len(filtered) != 2 || filtered[0] != "a/b/c/deep.log" || filtered[1] != "a/x.log" {
+		t.Errorf("filtered flattened listing is %v, expected the log files at any depth", filtered)
+	}
+}
@@ -0,0 +1,340 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/x/exp/term/ansi"
+	varibale "github.com/yorukot/superfile/src/config"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+const (
+	// When the ranks of all directories add up to more than this, every rank is aged
+	frecencyMaxTotalRank = 10000
+	// Ranks are multiplied by this when aging, directories whose rank drops below 1 are forgotten
+	frecencyAgingFactor = 0.9
+	// Results shown in the jump modal
+	jumpModalMaxResults = 100
+	// Visits are saved this long after the last one, so browsing doesn't write the database on every step
+	frecencySaveDelay = 2 * time.Second
+)
+
+// Visits of a directory, saved in frecency.json
+type frecencyEntry struct {
+	Rank       float64 `json:"rank"`
+	LastAccess int64   `json:"last_access"`
+}
+
+// Visited directories, loaded from frecency.json
+var frecencyDatabase = map[string]frecencyEntry{}
+
+// The visits are saved in the background, the mutex keeps the database from changing while it is saved
+var (
+	frecencyMutex     sync.Mutex
+	frecencySaveTimer *time.Timer
+)
+
+// Load the visited directories
+func loadFrecencyDatabase() {
+	jsonData, err := os.ReadFile(varibale.FrecencyFilea)
+	if err != nil {
+		outPutLog("Load frecency database function read superfile data error", err)
+		return
+	}
+
+	if len(jsonData) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(jsonData, &frecencyDatabase)
+	if err != nil {
+		outPutLog("Load frecency database function unmarshal superfile data error", err)
+	}
+}
+
+// Save the visited directories
+func saveFrecencyDatabase() {
+	frecencyMutex.Lock()
+	updatedData, err := json.Marshal(frecencyDatabase)
+	frecencyMutex.Unlock()
+	if err != nil {
+		outPutLog("Save frecency database function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.FrecencyFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save frecency database function write superfile data error", err)
+	}
+}
+
+// Record a visit of the directory, the database is saved once no directory was visited for a moment
+func recordFrecencyVisit(location string) {
+	frecencyMutex.Lock()
+	defer frecencyMutex.Unlock()
+	entry := frecencyDatabase[location]
+	entry.Rank++
+	entry.LastAccess = time.Now().Unix()
+	frecencyDatabase[location] = entry
+
+	ageFrecencyDatabase(frecencyDatabase)
+	if frecencySaveTimer == nil {
+		frecencySaveTimer = time.AfterFunc(frecencySaveDelay, saveFrecencyDatabase)
+	} else {
+		frecencySaveTimer.Reset(frecencySaveDelay)
+	}
+}
+
+// Save the visits still waiting for the save delay, before superfile quits
+func flushFrecencyDatabase() {
+	frecencyMutex.Lock()
+	pending := frecencySaveTimer != nil && frecencySaveTimer.Stop()
+	frecencyMutex.Unlock()
+	if pending {
+		saveFrecencyDatabase()
+	}
+}
+
+// Lower every rank once they add up to too much, so old directories are forgotten over time
+func ageFrecencyDatabase(database map[string]frecencyEntry) {
+	totalRank := 0.0
+	for _, entry := range database {
+		totalRank += entry.Rank
+	}
+	if totalRank <= frecencyMaxTotalRank {
+		return
+	}
+
+	for location, entry := range database {
+		entry.Rank *= frecencyAgingFactor
+		if entry.Rank < 1 {
+			delete(database, location)
+		} else {
+			database[location] = entry
+		}
+	}
+}
+
+// Return the score of the directory, recently visited directories score higher
+func frecencyScore(entry frecencyEntry, now int64) float64 {
+	age := now - entry.LastAccess
+	switch {
+	case age < 60*60:
+		return entry.Rank * 4
+	case age < 24*60*60:
+		return entry.Rank * 2
+	case age < 7*24*60*60:
+		return entry.Rank / 2
+	default:
+		return entry.Rank / 4
+	}
+}
+
+// Return whether the directory matches the keywords. Every keyword has to appear in the path
+// in order and the last one in the last path element, e.g. "proj api" matches ~/work/project/api.
+// Keywords are case insensitive when they are all lowercase
+func frecencyMatch(location string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	if strings.ToLower(strings.Join(keywords, " ")) == strings.Join(keywords, " ") {
+		location = strings.ToLower(location)
+	}
+
+	last := keywords[len(keywords)-1]
+	if !strings.Contains(filepath.Base(location), last) {
+		return false
+	}
+
+	remaining := location
+	for _, keyword := range keywords {
+		index := strings.Index(remaining, keyword)
+		if index < 0 {
+			return false
+		}
+		remaining = remaining[index+len(keyword):]
+	}
+	return true
+}
+
+// Return the existing directories of the database matching the keywords, best first
+func queryFrecencyDatabase(database map[string]frecencyEntry, keywords []string, now int64) []string {
+	type match struct {
+		location string
+		score    float64
+	}
+
+	matches := []match{}
+	for location, entry := range database {
+		if !frecencyMatch(location, keywords) {
+			continue
+		}
+		if info, err := os.Stat(location); err != nil || !info.IsDir() {
+			continue
+		}
+		matches = append(matches, match{location: location, score: frecencyScore(entry, now)})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].location < matches[j].location
+	})
+
+	locations := []string{}
+	for _, match := range matches {
+		locations = append(locations, match.location)
+	}
+	return locations
+}
+
+// Return the visited directories matching the keywords, best first. Used by "superfile query"
+func QueryFrecency(keywords []string) []string {
+	loadFrecencyDatabase()
+	return queryFrecencyDatabase(frecencyDatabase, keywords, time.Now().Unix())
+}
+
+// Open the jump modal to go to a visited directory
+func (m *model) openJumpModal() {
+	ti := textinput.New()
+	ti.Cursor.Style = modalCursorStyle
+	ti.Cursor.TextStyle = modalStyle
+	ti.TextStyle = modalStyle
+	ti.Prompt = filePanelTopDirectoryIconStyle.Render(icon.Search + icon.Space)
+	ti.Cursor.Blink = true
+	ti.Placeholder = "Keywords of the directory, e.g. \"proj api\""
+	ti.PlaceholderStyle = modalStyle
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = m.helpMenu.width - 6
+
+	m.jumpModal = jumpModal{
+		open:      true,
+		textInput: ti,
+	}
+	m.updateJumpModalResults()
+	m.firstTextInput = true
+}
+
+func (m *model) closeJumpModal() {
+	m.jumpModal = jumpModal{}
+}
+
+// Rank the visited directories again when the keywords changed
+func (m *model) updateJumpModalResults() {
+	query := m.jumpModal.textInput.Value()
+	if query == m.jumpModal.query && m.jumpModal.results != nil {
+		return
+	}
+
+	m.jumpModal.query = query
+	m.jumpModal.results = queryFrecencyDatabase(frecencyDatabase, strings.Fields(query), time.Now().Unix())
+	if len(m.jumpModal.results) > jumpModalMaxResults {
+		m.jumpModal.results = m.jumpModal.results[:jumpModalMaxResults]
+	}
+	m.jumpModal.cursor = 0
+	m.jumpModal.renderIndex = 0
+}
+
+// Go to the selected directory of the jump modal
+func (m *model) confirmJumpModal() {
+	if len(m.jumpModal.results) == 0 {
+		return
+	}
+	location := m.jumpModal.results[m.jumpModal.cursor]
+	m.closeJumpModal()
+
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	panel.directoryRecord[panel.location] = directoryRecord{
+		directoryCursor: panel.cursor,
+		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
+	}
+	panel.location = location
+	directoryRecord, hasRecord := panel.directoryRecord[panel.location]
+	if hasRecord {
+		panel.cursor = directoryRecord.directoryCursor
+		panel.render = directoryRecord.directoryRender
+	} else {
+		panel.cursor = 0
+		panel.render = 0
+	}
+	panel.searchBar.SetValue("")
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+	recordFrecencyVisit(location)
+}
+
+// Jump modal list up
+func (m *model) jumpModalListUp() {
+	if m.jumpModal.cursor > 0 {
+		m.jumpModal.cursor--
+		if m.jumpModal.cursor < m.jumpModal.renderIndex {
+			m.jumpModal.renderIndex--
+		}
+	} else if len(m.jumpModal.results) > 0 {
+		m.jumpModal.cursor = len(m.jumpModal.results) - 1
+		m.jumpModal.renderIndex = max(0, len(m.jumpModal.results)-jumpModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Jump modal list down
+func (m *model) jumpModalListDown() {
+	if m.jumpModal.cursor < len(m.jumpModal.results)-1 {
+		m.jumpModal.cursor++
+		if m.jumpModal.cursor >= m.jumpModal.renderIndex+jumpModalListHeight(m.helpMenu.height) {
+			m.jumpModal.renderIndex++
+		}
+	} else {
+		m.jumpModal.cursor = 0
+		m.jumpModal.renderIndex = 0
+	}
+}
+
+// Lines of the jump modal left for the results
+func jumpModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the jump modal
+func (m model) jumpModalRender() string {
+	width := m.helpMenu.width
+	content := " " + m.jumpModal.textInput.View() + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	results := m.jumpModal.results
+	if len(results) == 0 {
+		content += modalStyle.Render(" " + icon.Error + "  No visited directory matches")
+	}
+	for i := m.jumpModal.renderIndex; i < m.jumpModal.renderIndex+jumpModalListHeight(m.helpMenu.height) && i < len(results); i++ {
+		if i != m.jumpModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.jumpModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+		location := results[i]
+		if ansi.StringWidth(location) > width-2 {
+			location = truncateTextBeginning(location, width-2, "...")
+		}
+		content += cursor + modalStyle.Render(location)
+	}
+
+	count := "0/0"
+	if len(results) > 0 {
+		count = fmt.Sprintf("%d/%d", m.jumpModal.cursor+1, len(results))
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestFrecencyMatch(t *testing.T) {
+	testCases := []struct {
+		location string
+		keywords []string
+		match    bool
+	}{
+		{"/home/user/work/project/api", []string{"proj", "api"}, true},
+		{"/home/user/work/project/api", []string{"api", "proj"}, false},
+		{"/home/user/work/project/api/src", []string{"proj", "api"}, false},
+		{"/home/user/work/Project/API", []string{"proj", "api"}, true},
+		{"/home/user/work/project/api", []string{"API"}, false},
+		{"/home/user/work/project/api", nil, true},
+	}
+	for _, tc := range testCases {
+		if frecencyMatch(tc.location, tc.keywords) != tc.match {
+			t.Errorf("%s with keywords %v: expected match %t", tc.location, tc.keywords, tc.match)
+		}
+	}
+}
+
+func TestQueryFrecencyDatabase(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"project/api", "old/api", "often/api"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := int64(1000000000)
+	database := map[string]frecencyEntry{
+		filepath.Join(dir, "project/api"): {Rank: 2, LastAccess: now - 60},
+		filepath.Join(dir, "old/api"):     {Rank: 10, LastAccess: now - 30*24*60*60},
+		filepath.Join(dir, "often/api"):   {Rank: 3, LastAccess: now - 2*60*60},
+		filepath.Join(dir, "deleted/api"): {Rank: 100, LastAccess: now},
+	}
+
+	results := queryFrecencyDatabase(database, []string{"api"}, now)
+	expected := []string{"project/api", "often/api", "old/api"}
+	if len(results) != len(expected) {
+		t.Fatalf("got %v, expected %v", results, expected)
+	}
+	for i, name := range expected {
+		if results[i] != filepath.Join(dir, name) {
+			t.Errorf("result %d is %s, expected %s", i, results[i], name)
+		}
+	}
+
+	ageFrecencyDatabase(database)
+	if len(database) != 4 {
+		t.Error("the database should only be aged when the total rank is too high")
+	}
+	database[filepath.Join(dir, "big")] = frecencyEntry{Rank: frecencyMaxTotalRank}
+	database[filepath.Join(dir, "rare")] = frecencyEntry{Rank: 1}
+	ageFrecencyDatabase(database)
+	if _, ok := database[filepath.Join(dir, "rare")]; ok {
+		t.Error("directories whose rank drops below 1 should be forgotten")
+	}
+	if entry := database[filepath.Join(dir, "project/api")]; entry.Rank != 2*frecencyAgingFactor {
+		t.Errorf("rank after aging is %f, expected %f", entry.Rank, 2*frecencyAgingFactor)
+	}
+}
+
+func TestRecordFrecencyVisitSavesLater(t *testing.T) {
+	dir := t.TempDir()
+	frecencyFile := varibale.FrecencyFilea
+	varibale.FrecencyFilea = filepath.Join(dir, "frecency.json")
+	defer func(database map[string]frecencyEntry) {
+		varibale.FrecencyFilea = frecencyFile
+		frecencyDatabase = database
+	}(frecencyDatabase)
+	frecencyDatabase = map[string]frecencyEntry{}
+
+	recordFrecencyVisit(dir)
+	recordFrecencyVisit(dir)
+	if _, err := os.Stat(varibale.FrecencyFilea); err == nil {
+		t.Error("a visit should not write the database right away")
+	}
+
+	flushFrecencyDatabase()
+	frecencyDatabase = map[string]frecencyEntry{}
+	loadFrecencyDatabase()
+	if entry := frecencyDatabase[dir]; entry.Rank != 2 {
+		t.Errorf("both visits should be saved on flush, got rank %f", entry.Rank)
+	}
+}
@@ -41,7 +41,7 @@ func returnFocusType(focusPanel focusPanelType) filePanelFocusType {
 	return secondFocus
 }
 
-func returnFolderElement(location string, displayDotFile bool) (directoryElement []element) {
+func returnFolderElement(location string, displayDotFile bool, sortOptions sortOptions) (directoryElement []element) {
 
 	files, err := os.ReadDir(location)
 	if len(files) == 0 {
@@ -52,43 +52,43 @@ func returnFolderElement(location string, displayDotFile bool) (directoryElement
 		outPutLog("Return folder element function error", err)
 	}
 
-	sort.Slice(files, func(i, j int) bool {
-		if files[i].IsDir() && !files[j].IsDir() {
-			return true
-		}
-		if !files[i].IsDir() && files[j].IsDir() {
-			return false
-		}
-		return files[i].Name() < files[j].Name()
-	})
-
 	for _, item := range files {
-		fileInfo, err := item.Info()
-		if err != nil {
-			continue
-		}
-
-		if !displayDotFile && strings.HasPrefix(fileInfo.Name(), ".") {
-			continue
-		}
-		if fileInfo == nil {
+		newElement, ok := newElementFromDirEntry(location, item, displayDotFile)
+		if !ok {
 			continue
 		}
-		newElement := element{
-			name:      item.Name(),
-			directory: item.IsDir(),
-		}
-		if location == "/" {
-			newElement.location = location + item.Name()
-		} else {
-			newElement.location = filepath.Join(location, item.Name())
-		}
 		directoryElement = append(directoryElement, newElement)
 	}
 
+	sortElements(directoryElement, sortOptions)
+
 	return directoryElement
 }
 
+// Create the element of a directory entry, return false if it should be hidden
+func newElementFromDirEntry(location string, item os.DirEntry, displayDotFile bool) (element, bool) {
+	if !displayDotFile && strings.HasPrefix(item.Name(), ".") {
+		return element{}, false
+	}
+
+	fileInfo, err := item.Info()
+	if err != nil || fileInfo == nil {
+		return element{}, false
+	}
+
+	newElement := element{
+		name:      item.Name(),
+		directory: item.IsDir(),
+		info:      fileInfo,
+	}
+	if location == "/" {
+		newElement.location = location + item.Name()
+	} else {
+		newElement.location = filepath.Join(location, item.Name())
+	}
+	return newElement, true
+}
+
 func returnFolderElementBySearchString(location string, displayDotFile bool, searchString string) (folderElement []element) {
 
 	items, err := os.ReadDir(location)
@@ -119,6 +119,7 @@ func returnFolderElementBySearchString(location string, displayDotFile bool, sea
 			name:      item.Name(),
 			directory: item.IsDir(),
 			location:  folderElementLocation,
+			info:      fileInfo,
 		}
 
 	}
@@ -235,36 +236,6 @@ func renameIfDuplicate(destination string) (string, error) {
 	}
 }
 
-func pasteFile(src string, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		outPutLog("Paste file function open file error", err)
-	}
-	defer srcFile.Close()
-
-	dst, err = renameIfDuplicate(dst)
-	if err != nil {
-		outPutLog("Paste file function rename error", err)
-	}
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		outPutLog("Paste file function create file error", err)
-	}
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	_, err = io.Copy(dstFile, srcFile)
-	if err != nil {
-		outPutLog("Paste file function copy file error", err)
-	}
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func (m *model) returnMetaData() {
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 	cursor := panel.cursor
@@ -278,10 +249,15 @@ func (m *model) returnMetaData() {
 
 	// Obtaining metadata will take time. If metadata is obtained for every passing file, it will cause lag.
 	// Therefore, it is necessary to detect whether it is just browsing or stopping on that file or directory.
+	lastTimeCursorMoveMutex.Lock()
 	LastTimeCursorMove = [2]int{int(time.Now().UnixMicro()), cursor}
+	lastTimeCursorMoveMutex.Unlock()
 	time.Sleep(150 * time.Millisecond)
 
-	if LastTimeCursorMove[1] != cursor && m.focusPanel != metadataFocus {
+	lastTimeCursorMoveMutex.Lock()
+	moved := LastTimeCursorMove[1] != cursor
+	lastTimeCursorMoveMutex.Unlock()
+	if moved && m.focusPanel != metadataFocus {
 		return
 	}
 
@@ -320,7 +296,7 @@ func (m *model) returnMetaData() {
 	if fileInfo.IsDir() {
 		m.fileMetaData.metaData = append(m.fileMetaData.metaData, [2]string{"FolderName", fileInfo.Name()})
 		if m.focusPanel == metadataFocus {
-			m.fileMetaData.metaData = append(m.fileMetaData.metaData, [2]string{"FolderSize", formatFileSize(dirSize(filePath))})
+			m.fileMetaData.metaData = append(m.fileMetaData.metaData, [2]string{"FolderSize", formatFileSize(folderSize(filePath))})
 		}
 		m.fileMetaData.metaData = append(m.fileMetaData.metaData, [2]string{"FolderModifyDate", fileInfo.ModTime().String()})
 		m.fileMetaData.metaData = append(m.fileMetaData.metaData, [2]string{"FolderPermissions", fileInfo.Mode().String()})
@@ -407,6 +383,14 @@ func dirSize(path string) int64 {
 	return size
 }
 
+// Return the size of the directory, from the disk usage analyzer when it's already scanned
+func folderSize(path string) int64 {
+	if size, ok := diskUsage.size(path); ok {
+		return size
+	}
+	return dirSize(path)
+}
+
 // Count how many file in the directory
 func countFiles(dirPath string) (int, error) {
 	count := 0
@@ -0,0 +1,317 @@
+package internal
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lithammer/shortuuid"
+)
+
+// Git status of a repository, loaded in the background by running git status once
+type gitRepository struct {
+	root   string
+	branch string
+	// Status of every changed, untracked or ignored path relative to the root, untracked and
+	// ignored directories are listed as a whole
+	files map[string]gitFileStatus
+	// Highest status of the files under each directory
+	directories map[string]gitFileStatus
+	loaded      bool
+	loading     bool
+	// Files changed while loading, so the status has to be loaded again
+	stale bool
+}
+
+// Git status of every repository shown in a file panel
+type gitStatusCache struct {
+	mutex sync.Mutex
+	// Repository root of each directory, empty when the directory isn't in a repository
+	roots        map[string]string
+	repositories map[string]*gitRepository
+}
+
+var gitStatus = &gitStatusCache{
+	roots:        make(map[string]string),
+	repositories: make(map[string]*gitRepository),
+}
+
+// Return the root of the repository containing the directory, the caller must hold the mutex
+func (c *gitStatusCache) repositoryRoot(location string) string {
+	if root, ok := c.roots[location]; ok {
+		return root
+	}
+
+	root := ""
+	for directory := location; ; {
+		if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
+			root = directory
+			break
+		}
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			break
+		}
+		directory = parent
+	}
+	c.roots[location] = root
+	return root
+}
+
+// Return the root of the repository containing the location from the roots found by update, the
+// closest directory above it that was looked up decides. Used while rendering, so the filesystem
+// isn't read. The caller must hold the mutex
+func (c *gitStatusCache) knownRepositoryRoot(location string) string {
+	for directory := location; ; {
+		if root, ok := c.roots[directory]; ok {
+			return root
+		}
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return ""
+		}
+		directory = parent
+	}
+}
+
+// Start loading the status of the repositories of the locations that aren't loaded yet
+// or changed, return the git directories that should be watched for staging and checkouts
+func (c *gitStatusCache) update(locations []string) (gitDirectories []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, location := range locations {
+		root := c.repositoryRoot(location)
+		if root == "" {
+			continue
+		}
+
+		repository, ok := c.repositories[root]
+		if !ok {
+			repository = &gitRepository{root: root}
+			c.repositories[root] = repository
+		}
+		if (!repository.loaded || repository.stale) && !repository.loading {
+			repository.loading = true
+			repository.stale = false
+			go c.load(repository)
+		}
+
+		gitDirectory := filepath.Join(root, ".git")
+		if info, err := os.Stat(gitDirectory); err == nil && info.IsDir() && !arrayContains(gitDirectories, gitDirectory) {
+			gitDirectories = append(gitDirectories, gitDirectory)
+		}
+	}
+	return gitDirectories
+}
+
+// Mark the repository containing the location as changed
+func (c *gitStatusCache) invalidate(location string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Staging, commits and checkouts change the status of files anywhere in the repository, not
+	// only in the directories that are watched
+	if filepath.Base(location) == ".git" {
+		if repository, ok := c.repositories[filepath.Dir(location)]; ok {
+			repository.stale = true
+		}
+		return
+	}
+
+	// The directory may have become a repository or stopped being one
+	delete(c.roots, location)
+	root := c.repositoryRoot(location)
+	if repository, ok := c.repositories[root]; ok {
+		repository.stale = true
+	}
+}
+
+// Run git status for the repository and tell the model to render the new status
+func (c *gitStatusCache) load(repository *gitRepository) {
+	// --no-optional-locks keeps git status from writing the index, which would trigger the watcher again
+	cmd := exec.Command("git", "--no-optional-locks", "-C", repository.root, "status", "--porcelain", "-z", "--branch", "--ignored=matching", "--untracked-files=normal")
+	output, err := cmd.Output()
+	if err != nil {
+		outPutLog("Git status load repository error", repository.root, err)
+	}
+
+	branch, files := parseGitStatus(output)
+	directories := aggregateGitStatus(files)
+
+	c.mutex.Lock()
+	repository.branch = branch
+	repository.files = files
+	repository.directories = directories
+	repository.loaded = true
+	repository.loading = false
+	c.mutex.Unlock()
+
+	channel <- channelMessage{
+		messageId:   shortuuid.New(),
+		messageType: sendGitStatus,
+	}
+}
+
+// Parse the output of git status --porcelain -z --branch
+func parseGitStatus(output []byte) (branch string, files map[string]gitFileStatus) {
+	files = make(map[string]gitFileStatus)
+	records := bytes.Split(output, []byte{0})
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		if header, ok := strings.CutPrefix(record, "## "); ok {
+			branch = parseGitBranch(header)
+			continue
+		}
+		if len(record) < 4 {
+			continue
+		}
+
+		x, y := record[0], record[1]
+		path := strings.TrimSuffix(record[3:], "/")
+		files[path] = gitStatusFromCode(x, y)
+		// Renamed and copied files are followed by their original path
+		if x == 'R' || x == 'C' {
+			i++
+		}
+	}
+	return branch, files
+}
+
+// Return the branch name from the branch header of git status
+func parseGitBranch(header string) string {
+	if branch, ok := strings.CutPrefix(header, "No commits yet on "); ok {
+		return branch
+	}
+	if branch, ok := strings.CutPrefix(header, "Initial commit on "); ok {
+		return branch
+	}
+	if strings.HasPrefix(header, "HEAD (no branch)") {
+		return "HEAD"
+	}
+	branch, _, _ := strings.Cut(header, "...")
+	branch, _, _ = strings.Cut(branch, " ")
+	return branch
+}
+
+// Return the status of a file from its two letter status code
+func gitStatusFromCode(x byte, y byte) gitFileStatus {
+	switch {
+	case x == '!' && y == '!':
+		return gitIgnored
+	case x == '?' && y == '?':
+		return gitUntracked
+	case x == 'U' || y == 'U' || (x == 'A' && y == 'A') || (x == 'D' && y == 'D'):
+		return gitConflicted
+	case y != ' ':
+		return gitModified
+	default:
+		return gitStaged
+	}
+}
+
+// Return the highest status of the files under each directory, ignored files don't count
+func aggregateGitStatus(files map[string]gitFileStatus) map[string]gitFileStatus {
+	directories := make(map[string]gitFileStatus)
+	for path, status := range files {
+		if status == gitIgnored {
+			continue
+		}
+		for directory := filepath.Dir(filepath.FromSlash(path)); directory != "."; directory = filepath.Dir(directory) {
+			directory = filepath.ToSlash(directory)
+			if directories[directory] >= status {
+				break
+			}
+			directories[directory] = status
+		}
+	}
+	return directories
+}
+
+// Return the git status of the file and whether it's the status of the files under a directory
+func (c *gitStatusCache) fileStatus(location string) (status gitFileStatus, aggregate bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	root := c.knownRepositoryRoot(filepath.Dir(location))
+	repository, ok := c.repositories[root]
+	if root == "" || !ok || !repository.loaded {
+		return gitUnmodified, false
+	}
+
+	relativePath, err := filepath.Rel(root, location)
+	if err != nil {
+		return gitUnmodified, false
+	}
+	relativePath = filepath.ToSlash(relativePath)
+
+	if status, ok := repository.files[relativePath]; ok {
+		return status, false
+	}
+	if status, ok := repository.directories[relativePath]; ok {
+		return status, true
+	}
+
+	// Files in an untracked or ignored directory have the status of the directory
+	for directory := filepath.Dir(relativePath); directory != "." && directory != "/"; directory = filepath.Dir(directory) {
+		if status, ok := repository.files[directory]; ok && (status == gitUntracked || status == gitIgnored) {
+			return status, false
+		}
+	}
+	return gitUnmodified, false
+}
+
+// Return the current branch of the repository containing the directory
+func (c *gitStatusCache) branch(location string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	repository, ok := c.repositories[c.knownRepositoryRoot(location)]
+	if !ok {
+		return ""
+	}
+	return repository.branch
+}
+
+// Letter shown for the git status of a file, directories show a dot with the color of their files
+var gitStatusLetter = map[gitFileStatus]string{
+	gitModified:   "M",
+	gitStaged:     "S",
+	gitUntracked:  "?",
+	gitIgnored:    "!",
+	gitConflicted: "U",
+}
+
+// Render the git status marker shown between the cursor and the file icon
+func gitStatusMarker(location string) string {
+	status, aggregate := gitStatus.fileStatus(location)
+	if status == gitUnmodified {
+		return filePanelStyle.Render(" ")
+	}
+
+	marker := gitStatusLetter[status]
+	if aggregate {
+		marker = "•"
+	}
+	return gitStatusStyle(status).Render(marker)
+}
+
+func gitStatusStyle(status gitFileStatus) lipgloss.Style {
+	switch status {
+	case gitModified:
+		return gitModifiedStyle
+	case gitStaged:
+		return gitStagedStyle
+	case gitUntracked:
+		return gitUntrackedStyle
+	case gitIgnored:
+		return gitIgnoredStyle
+	case gitConflicted:
+		return gitConflictedStyle
+	}
+	return filePanelStyle
+}
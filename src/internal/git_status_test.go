@@ -0,0 +1,93 @@
+package internal
+
+import "testing"
+
+func TestParseGitStatus(t *testing.T) {
+	output := "## main...origin/main [ahead 1]\x00" +
+		" M src/model.go\x00" +
+		"M  README.md\x00" +
+		"MM src/internal/type.go\x00" +
+		"R  new.go\x00old.go\x00" +
+		"UU conflict.go\x00" +
+		"?? docs/\x00" +
+		"!! node_modules/\x00"
+
+	branch, files := parseGitStatus([]byte(output))
+	if branch != "main" {
+		t.Errorf("branch is %q, expected main", branch)
+	}
+
+	expected := map[string]gitFileStatus{
+		"src/model.go":         gitModified,
+		"README.md":            gitStaged,
+		"src/internal/type.go": gitModified,
+		"new.go":               gitStaged,
+		"conflict.go":          gitConflicted,
+		"docs":                 gitUntracked,
+		"node_modules":         gitIgnored,
+	}
+	if len(files) != len(expected) {
+		t.Errorf("got %d files, expected %d: %v", len(files), len(expected), files)
+	}
+	for path, status := range expected {
+		if files[path] != status {
+			t.Errorf("status of %s is %d, expected %d", path, files[path], status)
+		}
+	}
+
+	directories := aggregateGitStatus(files)
+	if directories["src"] != gitModified || directories["src/internal"] != gitModified {
+		t.Errorf("directories should have the highest status of their files: %v", directories)
+	}
+	if _, ok := directories["node_modules"]; ok {
+		t.Error("ignored files should not be aggregated")
+	}
+}
+
+func TestParseGitBranch(t *testing.T) {
+	testCases := map[string]string{
+		"main":                          "main",
+		"feature/x...origin/feature/x":  "feature/x",
+		"main...origin/main [behind 2]": "main",
+		"No commits yet on trunk":       "trunk",
+		"HEAD (no branch)":              "HEAD",
+	}
+	for header, expected := range testCases {
+		if branch := parseGitBranch(header); branch != expected {
+			t.Errorf("branch of %q is %q, expected %q", header, branch, expected)
+		}
+	}
+}
+
+func TestGitStatusCacheWithoutFilesystem(t *testing.T) {
+	root := "/nonexistent/repository"
+	cache := &gitStatusCache{
+		roots: map[string]string{root: root},
+		repositories: map[string]*gitRepository{root: {
+			root:        root,
+			branch:      "main",
+			loaded:      true,
+			files:       map[string]gitFileStatus{"src/deep/file.go": gitModified},
+			directories: map[string]gitFileStatus{"src": gitModified, "src/deep": gitModified},
+		}},
+	}
+
+	// Directories below a known root are found without looking for their .git
+	if status, aggregate := cache.fileStatus(root + "/src/deep"); status != gitModified || !aggregate {
+		t.Errorf("src/deep should show the status of its files, got %d %t", status, aggregate)
+	}
+	if status, _ := cache.fileStatus(root + "/src/deep/file.go"); status != gitModified {
+		t.Errorf("file.go should be modified, got %d", status)
+	}
+	if branch := cache.branch(root + "/src"); branch != "main" {
+		t.Errorf("src should be on main, got %q", branch)
+	}
+	if len(cache.roots) != 1 {
+		t.Error("rendering should not look up new repository roots")
+	}
+
+	cache.invalidate(root + "/.git")
+	if !cache.repositories[root].stale {
+		t.Error("a change in .git should reload the whole repository")
+	}
+}
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+// Return the path typed in the go to modal, "~" is the home directory and relative
+// paths are relative to the location of the file panel
+func expandGoToPath(location string, input string) string {
+	if input == "~" {
+		return varibale.HomeDir
+	}
+	if strings.HasPrefix(input, "~/") || strings.HasPrefix(input, "~"+string(os.PathSeparator)) {
+		return filepath.Join(varibale.HomeDir, input[2:])
+	}
+	if filepath.IsAbs(input) {
+		return filepath.Clean(input)
+	}
+	return filepath.Join(location, input)
+}
+
+// Split the typed path into the part already completed and the directory names starting
+// with the last path element, hidden directories are only listed when it starts with a dot
+func goToPathCompletions(location string, input string) (string, []string) {
+	if input == "~" {
+		return "~/", nil
+	}
+	completed := input[:strings.LastIndexAny(input, "/"+string(os.PathSeparator))+1]
+	prefix := input[len(completed):]
+
+	directory := location
+	if completed != "" {
+		directory = expandGoToPath(location, completed)
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return completed, nil
+	}
+
+	candidates := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		// Symlinks to directories are completed like directories
+		if info, err := os.Stat(filepath.Join(directory, name)); err != nil || !info.IsDir() {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	sort.Strings(candidates)
+	return completed, candidates
+}
+
+// Return the longest prefix shared by all the names
+func longestCommonPrefix(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// Open the go to modal to type the path to open in the focused file panel
+func (m *model) openGoToPathModal() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	ti := textinput.New()
+	ti.Cursor.Style = modalCursorStyle
+	ti.Cursor.TextStyle = modalStyle
+	ti.TextStyle = modalStyle
+	ti.Cursor.Blink = true
+	ti.Placeholder = "Absolute, relative or ~ path, tab to complete"
+	ti.PlaceholderStyle = modalStyle
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = modalWidth - 10
+
+	m.typingModal = typingModal{
+		location:   panel.location,
+		open:       true,
+		textInput:  ti,
+		typingType: goToPathTyping,
+	}
+	m.firstTextInput = true
+}
+
+// List the directories completing the typed path again when it changed
+func (m *model) updateGoToPathCompletions() {
+	value := m.typingModal.textInput.Value()
+	if value == m.typingModal.query {
+		return
+	}
+	m.typingModal.query = value
+	m.typingModal.err = ""
+	m.typingModal.completions = nil
+	if value == "" {
+		return
+	}
+	_, m.typingModal.completions = goToPathCompletions(m.typingModal.location, value)
+}
+
+// Complete the typed path like a shell, up to the next "/" when a single directory matches
+func (m *model) completeGoToPath() {
+	completed, candidates := goToPathCompletions(m.typingModal.location, m.typingModal.textInput.Value())
+	value := completed
+	switch len(candidates) {
+	case 0:
+		if completed != "~/" {
+			return
+		}
+	case 1:
+		value = completed + candidates[0] + "/"
+	default:
+		value = completed + longestCommonPrefix(candidates)
+	}
+
+	m.typingModal.textInput.SetValue(value)
+	m.typingModal.textInput.CursorEnd()
+	m.updateGoToPathCompletions()
+}
+
+// Open the typed path in the focused file panel, the cursor is put on the file when it is a file
+func (m *model) confirmGoToPath() {
+	path := expandGoToPath(m.typingModal.location, m.typingModal.textInput.Value())
+	info, err := os.Stat(path)
+	if err != nil {
+		m.typingModal.err = "No such file or directory"
+		return
+	}
+	m.cancelTypingModal()
+
+	location := path
+	targetFile := ""
+	if !info.IsDir() {
+		location = filepath.Dir(path)
+		targetFile = path
+	}
+
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	panel.directoryRecord[panel.location] = directoryRecord{
+		directoryCursor: panel.cursor,
+		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
+	}
+	panel.location = location
+	directoryRecord, hasRecord := panel.directoryRecord[panel.location]
+	if hasRecord && targetFile == "" {
+		panel.cursor = directoryRecord.directoryCursor
+		panel.render = directoryRecord.directoryRender
+	} else {
+		panel.cursor = 0
+		panel.render = 0
+	}
+	panel.targetFile = targetFile
+	panel.searchBar.SetValue("")
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+	recordFrecencyVisit(location)
+}
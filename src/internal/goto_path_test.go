@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestExpandGoToPath(t *testing.T) {
+	location := filepath.Join(string(os.PathSeparator), "work", "project")
+	tests := map[string]string{
+		"":          location,
+		"src/api":   filepath.Join(location, "src", "api"),
+		"../other":  filepath.Join(string(os.PathSeparator), "work", "other"),
+		"~":         varibale.HomeDir,
+		"~/Desktop": filepath.Join(varibale.HomeDir, "Desktop"),
+	}
+	for input, expected := range tests {
+		if got := expandGoToPath(location, input); got != expected {
+			t.Errorf("expandGoToPath(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestGoToPathCompletions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"docs", "downloads", "dotfiles", ".config", "src/api"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "do.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	completed, candidates := goToPathCompletions(dir, "do")
+	if completed != "" || !reflect.DeepEqual(candidates, []string{"docs", "dotfiles", "downloads"}) {
+		t.Errorf("files and other names should not be completed, got %q %v", completed, candidates)
+	}
+	if prefix := longestCommonPrefix(candidates); prefix != "do" {
+		t.Errorf("longest common prefix should be do, got %q", prefix)
+	}
+
+	completed, candidates = goToPathCompletions(dir, "src/a")
+	if completed != "src/" || !reflect.DeepEqual(candidates, []string{"api"}) {
+		t.Errorf("subdirectories should be completed, got %q %v", completed, candidates)
+	}
+
+	if _, candidates = goToPathCompletions(dir, ""); len(candidates) != 4 {
+		t.Errorf("hidden directories should only be completed after a dot, got %v", candidates)
+	}
+	if _, candidates = goToPathCompletions(dir, "."); !reflect.DeepEqual(candidates, []string{".config"}) {
+		t.Errorf("hidden directories should be completed after a dot, got %v", candidates)
+	}
+}
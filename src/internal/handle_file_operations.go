@@ -33,6 +33,7 @@ func (m *model) panelCreateNewFile() {
 	m.typingModal.location = panel.location
 	m.typingModal.open = true
 	m.typingModal.textInput = ti
+	m.typingModal.typingType = createItemTyping
 	m.firstTextInput = true
 
 	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
@@ -53,10 +54,10 @@ func (m *model) panelItemRename() {
 	ti.Cursor.Blink = true
 	ti.Placeholder = "New name"
 	ti.PlaceholderStyle = modalStyle
-	ti.SetValue(panel.element[panel.cursor].name)
+	ti.SetValue(filepath.Base(panel.element[panel.cursor].location))
 	ti.Focus()
 	ti.CharLimit = 156
-	ti.Width = m.fileModel.width - 4
+	ti.Width = panel.width - 4
 
 	m.fileModel.renaming = true
 	panel.renaming = true
@@ -125,7 +126,7 @@ func (m *model) deleteSingleItem() {
 	}
 
 	channel <- message
-	err := trashMacOrLinux(panel.element[panel.cursor].location)
+	item, err := trashMacOrLinux(panel.element[panel.cursor].location)
 
 	if err != nil {
 		p := m.processBarModel.process[id]
@@ -139,6 +140,7 @@ func (m *model) deleteSingleItem() {
 		p.doneTime = time.Now()
 		message.processNewState = p
 		channel <- message
+		recordJournal(journalTrash, []journalItem{item})
 	}
 	if panel.cursor == len(panel.element)-1 {
 		panel.cursor--
@@ -172,6 +174,7 @@ func (m model) deleteMultipleItems() {
 
 		channel <- message
 
+		trashed := []journalItem{}
 		for _, filePath := range panel.selected {
 
 			p := m.processBarModel.process[id]
@@ -182,7 +185,7 @@ func (m model) deleteMultipleItems() {
 				message.processNewState = p
 				channel <- message
 			}
-			err := trashMacOrLinux(filePath)
+			item, err := trashMacOrLinux(filePath)
 
 			if err != nil {
 				p.state = failure
@@ -192,6 +195,7 @@ func (m model) deleteMultipleItems() {
 				m.processBarModel.process[id] = p
 				break
 			} else {
+				trashed = append(trashed, item)
 				if p.done == p.total {
 					p.state = successful
 					message.processNewState = p
@@ -200,6 +204,7 @@ func (m model) deleteMultipleItems() {
 				m.processBarModel.process[id] = p
 			}
 		}
+		recordJournal(journalTrash, trashed)
 	}
 
 	if panel.cursor >= len(panel.element)-len(panel.selected)-1 {
@@ -459,8 +464,14 @@ func (m *model) cutMultipleItem() {
 
 // Paste all clipboard items
 func (m model) pasteItem() {
-	id := shortuuid.New()
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	m.pasteItemsTo(m.copyItems, panel.location)
+}
+
+// Copy or move the items into the directory, tracked in the process bar
+func (m model) pasteItemsTo(items copyItems, location string) {
+	id := shortuuid.New()
+	m.copyItems = items
 
 	if len(m.copyItems.items) == 0 {
 		return
@@ -503,6 +514,9 @@ func (m model) pasteItem() {
 	channel <- message
 
 	p := m.processBarModel.process[id]
+	moved := []journalItem{}
+	// The decision the user chose to apply to every conflict of the paste
+	var decided *pasteConflictDecision
 	for _, filePath := range m.copyItems.items {
 		var err error
 		if m.copyItems.cut && !isExternalDiskPath(filePath) {
@@ -514,18 +528,73 @@ func (m model) pasteItem() {
 			p.name = icon.Copy + icon.Space + filepath.Base(filePath)
 		}
 
+		destination := filepath.Join(location, path.Base(filePath))
+		overwrite := false
+		if _, err := os.Lstat(destination); err == nil {
+			// An item pasted into itself or its own directory can only get another name
+			action := renameConflict
+			if !isSubPath(destination, filePath) {
+				if decided == nil {
+					decision := m.askPasteConflict(id, filePath, destination)
+					if decision.applyToAll {
+						decided = &decision
+					}
+					action = decision.action
+				} else {
+					action = decided.action
+				}
+			}
+
+			switch pasteConflictOutcome(action, filePath, destination) {
+			case skipConflict:
+				continue
+			case overwriteConflict:
+				overwrite = true
+			default:
+				destination, err = renameIfDuplicate(destination)
+				if err != nil {
+					outPutLog("Paste item function rename error", err)
+					continue
+				}
+			}
+		}
+
+		// An overwriting item is pasted next to the existing one first, which is only replaced once
+		// the paste worked
+		target := destination
+		if overwrite {
+			target = pasteTempName(destination)
+		}
+
 		errMessage := "cut item error"
-		if m.copyItems.cut && !isExternalDiskPath(filePath) {
-			err = moveElement(filePath, filepath.Join(panel.location, path.Base(filePath)))
+		move := m.copyItems.cut && !isExternalDiskPath(filePath)
+		if move {
+			err = moveElement(filePath, target)
 		} else {
-			newModel, err := pasteDir(filePath, filepath.Join(panel.location, path.Base(filePath)), id, m)
+			var newModel model
+			newModel, err = pasteDir(filePath, target, id, m)
 			if err != nil {
 				errMessage = "paste item error"
 			}
 			m = newModel
-			if m.copyItems.cut {
-				os.RemoveAll(filePath)
+		}
+		if overwrite {
+			if err == nil {
+				err = replacePastedItem(target, destination)
+				if err != nil {
+					errMessage = "overwrite item error"
+				}
 			}
+			if err != nil && move {
+				os.Rename(target, filePath)
+			} else if err != nil {
+				os.RemoveAll(target)
+			}
+		}
+		if err == nil && move {
+			moved = append(moved, newJournalItem(filePath, destination))
+		} else if err == nil && m.copyItems.cut {
+			os.RemoveAll(filePath)
 		}
 		p = m.processBarModel.process[id]
 		if err != nil {
@@ -537,6 +606,8 @@ func (m model) pasteItem() {
 			break
 		}
 	}
+	// Items copied to an external disk and removed after can not be moved back in one rename
+	recordJournal(journalMove, moved)
 
 	p.state = successful
 	p.done = totalFiles
@@ -548,6 +619,29 @@ func (m model) pasteItem() {
 	m.copyItems.cut = false
 }
 
+// Return a free hidden name next to the destination to paste an item that overwrites it
+func pasteTempName(destination string) string {
+	return filepath.Join(filepath.Dir(destination), "."+filepath.Base(destination)+".superfile-"+shortuuid.New())
+}
+
+// Put the pasted item in place of the one it overwrites, the old item is only removed once the new
+// one took its name
+func replacePastedItem(pasted string, destination string) error {
+	backup := pasteTempName(destination)
+	if err := os.Rename(destination, backup); err != nil {
+		return err
+	}
+	if err := os.Rename(pasted, destination); err != nil {
+		os.Rename(backup, destination)
+		return err
+	}
+	// The paste is done, an old item that can't be removed fully is left hidden
+	if err := os.RemoveAll(backup); err != nil {
+		outPutLog("Paste item function remove overwritten item error", err)
+	}
+	return nil
+}
+
 // Extrach compress file
 func (m model) extractFile() {
 	var err error
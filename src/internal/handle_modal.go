@@ -29,13 +29,18 @@ func (m *model) createItem() {
 		f, err := os.Create(path)
 		if err != nil {
 			outPutLog("Create item func (m *model)tion create file error", err)
+		} else {
+			recordJournal(journalCreate, []journalItem{newJournalItem("", path)})
 		}
 		defer f.Close()
 	} else {
 		path := m.typingModal.location + "/" + m.typingModal.textInput.Value()
+		_, existed := os.Stat(path)
 		err := os.MkdirAll(path, 0755)
 		if err != nil {
 			outPutLog("Create item func (m *model)tion create folder error", err)
+		} else if os.IsNotExist(existed) {
+			recordJournal(journalCreate, []journalItem{newJournalItem("", filepath.Clean(path))})
 		}
 	}
 	m.typingModal.open = false
@@ -55,12 +60,14 @@ func (m *model) cancelReanem() {
 func (m *model) confirmRename() {
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 	oldPath := panel.element[panel.cursor].location
-	newPath := panel.location + "/" + panel.rename.Value()
+	newPath := filepath.Join(filepath.Dir(oldPath), panel.rename.Value())
 
 	// Rename the file
 	err := os.Rename(oldPath, newPath)
 	if err != nil {
 		outPutLog("Confirm func (m *model)tion rename error", err)
+	} else if oldPath != newPath {
+		recordJournal(journalRename, []journalItem{newJournalItem(oldPath, newPath)})
 	}
 
 	m.fileModel.renaming = false
@@ -7,6 +7,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	varibale "github.com/yorukot/superfile/src/config"
 )
@@ -23,12 +24,83 @@ func (m *model) changeFilePanelMode() {
 	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
 }
 
+// Toggle file panel view mode (list view or details view)
+func (m *model) toggleDetailsView() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if panel.viewMode == detailsView {
+		panel.viewMode = listView
+	} else {
+		panel.viewMode = detailsView
+	}
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Change to the next sort type of the directory in the focused file panel
+func (m *model) nextSortType() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	options := getSortOptions(panel.location)
+	if options.SortType == sortByExtension {
+		options.SortType = sortByName
+	} else {
+		options.SortType++
+	}
+	setSortOptions(panel.location, options)
+	m.refreshFilePanelsWithLocation(panel.location)
+}
+
+// Reverse the sort order of the directory in the focused file panel
+func (m *model) reverseSortOrder() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	options := getSortOptions(panel.location)
+	options.Reversed = !options.Reversed
+	setSortOptions(panel.location, options)
+	m.refreshFilePanelsWithLocation(panel.location)
+}
+
+// Force every file panel showing the location to get its elements again
+func (m *model) refreshFilePanelsWithLocation(location string) {
+	for i := range m.fileModel.filePanels {
+		panel := m.fileModel.filePanels[i]
+		if panel.location == location || (panel.viewMode == treeView && panel.expanded(location)) || panel.flattenContains(location) {
+			// A load in progress would start over on every change, it is loaded again once after it
+			if panel.directoryLoad != nil {
+				m.fileModel.filePanels[i].refreshPending = true
+				continue
+			}
+			m.fileModel.filePanels[i].lastTimeGetElement = time.Time{}
+		}
+	}
+}
+
+// Move the cursor to the target file once the elements of the panel are loaded
+func (panel *filePanel) moveCursorToTargetFile(mainPanelHeight int) {
+	if panel.targetFile == "" {
+		return
+	}
+
+	for i, item := range panel.element {
+		if item.location == panel.targetFile {
+			panel.cursor = i
+			panel.render = 0
+			if i >= panelElementHeight(mainPanelHeight) {
+				panel.render = i - panelElementHeight(mainPanelHeight) + 1
+			}
+			break
+		}
+	}
+	panel.targetFile = ""
+}
+
 // Back to parent directory
 func (m *model) parentDirectory() {
+	if m.collapseTreeParent() {
+		return
+	}
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 	panel.directoryRecord[panel.location] = directoryRecord{
 		directoryCursor: panel.cursor,
 		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
 	}
 	fullPath := panel.location
 	parentDir := path.Dir(fullPath)
@@ -40,8 +112,13 @@ func (m *model) parentDirectory() {
 	} else {
 		panel.cursor = 0
 		panel.render = 0
+		// Slide the columns: the directory we left stays under the cursor
+		if m.fileModel.millerColumns {
+			panel.targetFile = fullPath
+		}
 	}
 	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+	recordFrecencyVisit(panel.location)
 }
 
 // Enter director or open file with default application
@@ -52,10 +129,21 @@ func (m *model) enterPanel() {
 		return
 	}
 
+	if panel.searchMode == recursiveSearchMode && panel.searchBar.Value() != "" {
+		m.enterRecursiveSearchResult()
+		return
+	}
+
+	if panel.viewMode == treeView && !panel.flatten && panel.element[panel.cursor].directory {
+		m.toggleTreeDirectory()
+		return
+	}
+
 	if panel.element[panel.cursor].directory {
 		panel.directoryRecord[panel.location] = directoryRecord{
 			directoryCursor: panel.cursor,
 			directoryRender: panel.render,
+			expanded:        panel.expanded(panel.location),
 		}
 		panel.location = panel.element[panel.cursor].location
 		directoryRecord, hasRecord := panel.directoryRecord[panel.location]
@@ -66,7 +154,11 @@ func (m *model) enterPanel() {
 			panel.cursor = 0
 			panel.render = 0
 		}
-		panel.searchBar.SetValue("")
+		recordFrecencyVisit(panel.location)
+		// A filter stays on the panel until it is cleared
+		if panel.searchMode != filterSearchMode {
+			panel.searchBar.SetValue("")
+		}
 	} else if !panel.element[panel.cursor].directory {
 		fileInfo, err := os.Lstat(panel.element[panel.cursor].location)
 		if err != nil {
@@ -87,6 +179,7 @@ func (m *model) enterPanel() {
 			}
 
 			m.fileModel.filePanels[m.filePanelFocusIndex].location = absLinkPath
+			recordFrecencyVisit(absLinkPath)
 			return
 		}
 
@@ -113,6 +206,7 @@ func (m *model) sidebarSelectDirectory() {
 	panel.directoryRecord[panel.location] = directoryRecord{
 		directoryCursor: panel.cursor,
 		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
 	}
 
 	panel.location = m.sidebarModel.directories[m.sidebarModel.cursor].location
@@ -126,6 +220,7 @@ func (m *model) sidebarSelectDirectory() {
 	}
 	panel.focusType = focus
 	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+	recordFrecencyVisit(panel.location)
 }
 
 // Select all item in the file panel (only work on select mode)
@@ -169,14 +264,19 @@ func (m *model) toggleDotFileController() {
 // Focus on search bar
 func (m *model) searchBarFocus() {
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
-	if panel.searchBar.Focused() {
+	if panel.searchBar.Focused() && panel.searchMode == fuzzySearchMode {
 		panel.searchBar.Blur()
 	} else {
+		if panel.searchMode != fuzzySearchMode {
+			panel.searchBar.SetValue("")
+		}
+		panel.searchMode = fuzzySearchMode
+		panel.searchBar.Placeholder = searchBarPlaceholder(fuzzySearchMode)
 		panel.searchBar.Focus()
 	}
 
 	// config search bar width
-	panel.searchBar.Width = m.fileModel.width - 4
+	panel.searchBar.Width = panel.width - 4
 	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
 }
 
@@ -61,25 +61,11 @@ func (m *model) createNewFilePanel() {
 		searchBar:       generateSearchBar(),
 	})
 
-	if m.fileModel.filePreview.open {
-		// File preview panel width same as file panel
-		if Config.FilePreviewWidth == 0 {
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth - (4 + (len(m.fileModel.filePanels))*2)) / (len(m.fileModel.filePanels) + 1)
-		} else {
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth) / Config.FilePreviewWidth
-		}
-	}
-
 	m.fileModel.filePanels[m.filePanelFocusIndex].focusType = noneFocus
 	m.fileModel.filePanels[m.filePanelFocusIndex+1].focusType = returnFocusType(m.focusPanel)
-	m.fileModel.width = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width - (4 + (len(m.fileModel.filePanels)-1)*2)) / len(m.fileModel.filePanels)
 	m.filePanelFocusIndex++
 
-	m.fileModel.maxFilePanel = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width) / 20
-
-	for i := range m.fileModel.filePanels {
-		m.fileModel.filePanels[i].searchBar.Width = m.fileModel.width - 4
-	}
+	m.applyLayout()
 }
 
 // Close current focus file panel
@@ -88,52 +74,22 @@ func (m *model) closeFilePanel() {
 		return
 	}
 
+	m.fileModel.filePanels[m.filePanelFocusIndex].cancelDirectoryLoad()
+	m.fileModel.filePanels[m.filePanelFocusIndex].cancelRecursiveSearch()
 	m.fileModel.filePanels = append(m.fileModel.filePanels[:m.filePanelFocusIndex], m.fileModel.filePanels[m.filePanelFocusIndex+1:]...)
 
-	if m.fileModel.filePreview.open {
-		// File preview panel width same as file panel
-		if Config.FilePreviewWidth == 0 {
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth - (4 + (len(m.fileModel.filePanels))*2)) / (len(m.fileModel.filePanels) + 1)
-		} else {
-
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth) / Config.FilePreviewWidth
-		}
-	}
-
 	if m.filePanelFocusIndex != 0 {
 		m.filePanelFocusIndex--
 	}
 
-	m.fileModel.width = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width - (4 + (len(m.fileModel.filePanels)-1)*2)) / len(m.fileModel.filePanels)
 	m.fileModel.filePanels[m.filePanelFocusIndex].focusType = returnFocusType(m.focusPanel)
 
-	m.fileModel.maxFilePanel = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width) / 20
-
-	for i := range m.fileModel.filePanels {
-		m.fileModel.filePanels[i].searchBar.Width = m.fileModel.width - 4
-	}
+	m.applyLayout()
 }
 
 func (m *model) toggleFilePreviewPanel() {
 	m.fileModel.filePreview.open = !m.fileModel.filePreview.open
-	m.fileModel.filePreview.width = 0
-	if m.fileModel.filePreview.open {
-		// File preview panel width same as file panel
-		if Config.FilePreviewWidth == 0 {
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth - (4 + (len(m.fileModel.filePanels))*2)) / (len(m.fileModel.filePanels) + 1)
-		} else {
-			m.fileModel.filePreview.width = (m.fullWidth - Config.SidebarWidth) / Config.FilePreviewWidth
-		}
-	}
-
-	m.fileModel.width = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width - (4 + (len(m.fileModel.filePanels)-1)*2)) / len(m.fileModel.filePanels)
-
-	m.fileModel.maxFilePanel = (m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width) / 20
-
-	for i := range m.fileModel.filePanels {
-		m.fileModel.filePanels[i].searchBar.Width = m.fileModel.width - 4
-	}
-
+	m.applyLayout()
 }
 
 // Focus on next file panel
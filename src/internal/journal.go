@@ -0,0 +1,464 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+	varibale "github.com/yorukot/superfile/src/config"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// Operations remembered by the journal
+const journalMaxLength = 100
+
+type journalOperation string
+
+const (
+	journalRename journalOperation = "rename"
+	journalCreate journalOperation = "create"
+	journalMove   journalOperation = "move"
+	journalTrash  journalOperation = "trash"
+)
+
+// A file operation with what is needed to undo it
+type journalEntry struct {
+	Operation journalOperation `json:"operation"`
+	Time      time.Time        `json:"time"`
+	Items     []journalItem    `json:"items"`
+}
+
+// A file the operation moved from one path to another. Created files have no From, trashed
+// files are moved To the trash can with the trash info file that records where they came from
+type journalItem struct {
+	From      string `json:"from,omitempty"`
+	To        string `json:"to"`
+	Directory bool   `json:"directory,omitempty"`
+	TrashInfo string `json:"trash_info,omitempty"`
+	// Modification time of the file where it is now, the operation is not undone or redone
+	// when the file changed since
+	ModTime time.Time `json:"mod_time"`
+}
+
+// The operations, oldest first. The entries from Position on were undone and can be redone
+type fileJournal struct {
+	mutex    sync.Mutex
+	Entries  []journalEntry `json:"entries"`
+	Position int            `json:"position"`
+}
+
+var journal = &fileJournal{}
+
+// Load the journal of the operations done in the previous superfile
+func loadJournal() {
+	jsonData, err := os.ReadFile(varibale.JournalFilea)
+	if err != nil {
+		outPutLog("Load journal function read superfile data error", err)
+		return
+	}
+
+	if len(jsonData) == 0 {
+		return
+	}
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	err = json.Unmarshal(jsonData, journal)
+	if err != nil {
+		outPutLog("Load journal function unmarshal superfile data error", err)
+	}
+	journal.Position = max(0, min(journal.Position, len(journal.Entries)))
+}
+
+// Save the journal, the caller must hold the mutex
+func (j *fileJournal) save() {
+	updatedData, err := json.Marshal(j)
+	if err != nil {
+		outPutLog("Save journal function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.JournalFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save journal function write superfile data error", err)
+	}
+}
+
+// Return the item of the file that is now at the path
+func newJournalItem(from string, to string) journalItem {
+	item := journalItem{From: from, To: to}
+	if info, err := os.Lstat(to); err == nil {
+		item.Directory = info.IsDir()
+		item.ModTime = info.ModTime()
+	}
+	return item
+}
+
+// Record the operation, the undone operations can not be redone anymore
+func recordJournal(operation journalOperation, items []journalItem) {
+	if len(items) == 0 {
+		return
+	}
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	journal.Entries = append(journal.Entries[:journal.Position], journalEntry{
+		Operation: operation,
+		Time:      time.Now(),
+		Items:     items,
+	})
+	if len(journal.Entries) > journalMaxLength {
+		journal.Entries = journal.Entries[len(journal.Entries)-journalMaxLength:]
+	}
+	journal.Position = len(journal.Entries)
+	journal.save()
+}
+
+// Return an error when the file is missing or was modified since the operation. A directory changes
+// its modification time with every entry added or removed in it, undoing the operations on them
+// included, so only files are compared. Only empty directories are removed and renaming is safe
+func checkJournalFile(path string, item journalItem) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("%s no longer exists", path)
+	}
+	if !item.Directory && !info.ModTime().Equal(item.ModTime) {
+		return fmt.Errorf("%s was modified since", path)
+	}
+	return nil
+}
+
+// Return an error when something is at the path
+func checkJournalFree(path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	return nil
+}
+
+// Return an error when undoing the operation is no longer safe
+func (entry journalEntry) checkUndo() error {
+	for _, item := range entry.Items {
+		if err := checkJournalFile(item.To, item); err != nil {
+			return err
+		}
+		if entry.Operation != journalCreate {
+			if err := checkJournalFree(item.From); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Return an error when redoing the operation is no longer safe
+func (entry journalEntry) checkRedo() error {
+	for _, item := range entry.Items {
+		if entry.Operation != journalCreate {
+			if err := checkJournalFile(item.From, item); err != nil {
+				return err
+			}
+		}
+		// The trash can picks a free name itself
+		if entry.Operation != journalTrash {
+			if err := checkJournalFree(item.To); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Undo the operation, the items keep the modification time they have after it. When an item
+// fails, the items already undone are done again so the operation stays whole
+func (entry *journalEntry) undo() error {
+	for i := range entry.Items {
+		if err := entry.undoItem(&entry.Items[i]); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if err := entry.redoItem(&entry.Items[j]); err != nil {
+					outPutLog("Undo journal function roll back error", err)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Do the undone operation again, rolled back like undo when an item fails
+func (entry *journalEntry) redo() error {
+	for i := range entry.Items {
+		if err := entry.redoItem(&entry.Items[i]); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if err := entry.undoItem(&entry.Items[j]); err != nil {
+					outPutLog("Redo journal function roll back error", err)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Undo the operation for one item
+func (entry journalEntry) undoItem(item *journalItem) error {
+	var err error
+	switch entry.Operation {
+	case journalCreate:
+		// A directory is only removed while it is empty
+		err = os.Remove(item.To)
+	case journalTrash:
+		err = os.Rename(item.To, item.From)
+		if err == nil && item.TrashInfo != "" {
+			if err := os.Remove(item.TrashInfo); err != nil {
+				outPutLog("Undo journal function remove trash info error", err)
+			}
+		}
+	default:
+		err = os.Rename(item.To, item.From)
+	}
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(item.From); err == nil {
+		item.ModTime = info.ModTime()
+	}
+	return nil
+}
+
+// Do the operation again for one item
+func (entry journalEntry) redoItem(item *journalItem) error {
+	var err error
+	switch entry.Operation {
+	case journalCreate:
+		if item.Directory {
+			err = os.Mkdir(item.To, 0755)
+		} else {
+			var f *os.File
+			f, err = os.Create(item.To)
+			if err == nil {
+				f.Close()
+			}
+		}
+	case journalTrash:
+		// The file can get another name in the trash can than the first time
+		var trashed journalItem
+		trashed, err = trashMacOrLinux(item.From)
+		if err == nil {
+			*item = trashed
+		}
+	default:
+		err = os.Rename(item.From, item.To)
+	}
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(item.To); err == nil {
+		item.ModTime = info.ModTime()
+	}
+	return nil
+}
+
+// Return the directories the operation changed, to load the file panels showing them again
+func (entry journalEntry) directories() []string {
+	directories := []string{}
+	for _, item := range entry.Items {
+		for _, path := range []string{item.From, item.To} {
+			if path != "" && !arrayContains(directories, filepath.Dir(path)) {
+				directories = append(directories, filepath.Dir(path))
+			}
+		}
+	}
+	return directories
+}
+
+// Describe the operation in one line
+func (entry journalEntry) description() string {
+	if len(entry.Items) == 0 {
+		return string(entry.Operation)
+	}
+	name := filepath.Base(entry.Items[0].To)
+	if entry.Operation == journalTrash {
+		name = filepath.Base(entry.Items[0].From)
+	}
+	if len(entry.Items) > 1 {
+		name = strconv.Itoa(len(entry.Items)) + " items"
+	}
+	switch entry.Operation {
+	case journalRename:
+		return "Rename " + filepath.Base(entry.Items[0].From) + " → " + name
+	case journalCreate:
+		return "Create " + name
+	case journalMove:
+		return "Move " + name + " → " + filepath.Dir(entry.Items[0].To)
+	case journalTrash:
+		return "Trash " + name
+	}
+	return string(entry.Operation) + " " + name
+}
+
+// Tell the user why the operation was not undone or redone
+func (m *model) journalWarn(title string, err error) {
+	m.warnModal = warnModal{
+		open:     true,
+		warnType: noticeWarn,
+		title:    title,
+		content:  err.Error(),
+	}
+}
+
+// Undo the last operation that was not undone yet
+func (m *model) undoOperation() {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	if journal.Position == 0 {
+		return
+	}
+	entry := &journal.Entries[journal.Position-1]
+	if err := entry.checkUndo(); err != nil {
+		m.journalWarn("Can not undo \""+entry.description()+"\"", err)
+		return
+	}
+	err := entry.undo()
+	for _, directory := range entry.directories() {
+		m.refreshFilePanelsWithLocation(directory)
+	}
+	if err != nil {
+		outPutLog("Undo journal function error", err)
+		m.journalWarn("Failed to undo \""+entry.description()+"\"", err)
+		// Rolling back can give trashed items new names
+		journal.save()
+		return
+	}
+	journal.Position--
+	journal.save()
+}
+
+// Redo the last undone operation
+func (m *model) redoOperation() {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	if journal.Position == len(journal.Entries) {
+		return
+	}
+	entry := &journal.Entries[journal.Position]
+	if err := entry.checkRedo(); err != nil {
+		m.journalWarn("Can not redo \""+entry.description()+"\"", err)
+		return
+	}
+	err := entry.redo()
+	for _, directory := range entry.directories() {
+		m.refreshFilePanelsWithLocation(directory)
+	}
+	if err != nil {
+		outPutLog("Redo journal function error", err)
+		m.journalWarn("Failed to redo \""+entry.description()+"\"", err)
+		// Rolling back can give trashed items new names
+		journal.save()
+		return
+	}
+	journal.Position++
+	journal.save()
+}
+
+// Open the journal popup, the most recent operation is listed first
+func (m *model) openJournalModal() {
+	m.journalModal = journalModal{open: true}
+}
+
+func (m *model) closeJournalModal() {
+	m.journalModal = journalModal{}
+}
+
+// Return the number of operations in the journal
+func journalLength() int {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	return len(journal.Entries)
+}
+
+// Journal modal list up
+func (m *model) journalModalListUp() {
+	length := journalLength()
+	if m.journalModal.cursor > 0 {
+		m.journalModal.cursor--
+		if m.journalModal.cursor < m.journalModal.renderIndex {
+			m.journalModal.renderIndex--
+		}
+	} else if length > 0 {
+		m.journalModal.cursor = length - 1
+		m.journalModal.renderIndex = max(0, length-journalModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Journal modal list down
+func (m *model) journalModalListDown() {
+	length := journalLength()
+	if m.journalModal.cursor < length-1 {
+		m.journalModal.cursor++
+		if m.journalModal.cursor >= m.journalModal.renderIndex+journalModalListHeight(m.helpMenu.height) {
+			m.journalModal.renderIndex++
+		}
+	} else {
+		m.journalModal.cursor = 0
+		m.journalModal.renderIndex = 0
+	}
+}
+
+// Lines of the journal modal left for the operations
+func journalModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the journal popup, undone operations are dimmed
+func (m model) journalModalRender() string {
+	width := m.helpMenu.width
+	journal.mutex.Lock()
+	entries := append([]journalEntry{}, journal.Entries...)
+	position := journal.Position
+	journal.mutex.Unlock()
+
+	content := " " + filePanelTopDirectoryIconStyle.Render(icon.Directory+icon.Space) + filePanelTopPathStyle.Render("Journal") + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	if len(entries) == 0 {
+		content += modalStyle.Render("  No file operations yet")
+	}
+	for i := m.journalModal.renderIndex; i < m.journalModal.renderIndex+journalModalListHeight(m.helpMenu.height) && i < len(entries); i++ {
+		if i != m.journalModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.journalModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		index := len(entries) - 1 - i
+		line := entries[index].Time.Format("01-02 15:04") + "  " + entries[index].description()
+		if index >= position {
+			line += " (undone)"
+		}
+		if ansi.StringWidth(line) > width-4 {
+			line = truncateText(line, width-4, "...")
+		}
+		if index == position-1 {
+			content += cursor + helpMenuHotkeyStyle.Render(line)
+		} else {
+			content += cursor + modalStyle.Render(line)
+		}
+	}
+
+	count := "0/0"
+	if len(entries) > 0 {
+		count = fmt.Sprintf("%d/%d", m.journalModal.cursor+1, len(entries))
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rkoesters/xdg/basedir"
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestJournalUndoRedo(t *testing.T) {
+	dir := t.TempDir()
+	journalFile := varibale.JournalFilea
+	varibale.JournalFilea = filepath.Join(dir, "journal.json")
+	defer func() {
+		varibale.JournalFilea = journalFile
+		journal = &fileJournal{}
+	}()
+	journal = &fileJournal{}
+
+	oldPath := filepath.Join(dir, "old.txt")
+	newPath := filepath.Join(dir, "new.txt")
+	if err := os.WriteFile(oldPath, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+	recordJournal(journalRename, []journalItem{newJournalItem(oldPath, newPath)})
+	created := filepath.Join(dir, "created")
+	if err := os.Mkdir(created, 0755); err != nil {
+		t.Fatal(err)
+	}
+	recordJournal(journalCreate, []journalItem{newJournalItem("", created)})
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: dir}}}}
+	m.undoOperation()
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("undo should remove the created directory")
+	}
+	m.undoOperation()
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Error("undo should rename new.txt back to old.txt")
+	}
+	if m.warnModal.open {
+		t.Errorf("undo should not warn: %s", m.warnModal.content)
+	}
+
+	m.redoOperation()
+	if _, err := os.Stat(newPath); err != nil {
+		t.Error("redo should rename old.txt to new.txt again")
+	}
+
+	// The journal is saved with the undone operations
+	journal = &fileJournal{}
+	loadJournal()
+	if len(journal.Entries) != 2 || journal.Position != 1 {
+		t.Errorf("loaded journal should have 2 entries at 1, got %d at %d", len(journal.Entries), journal.Position)
+	}
+
+	// A new operation drops the undone ones
+	recordJournal(journalCreate, []journalItem{newJournalItem("", newPath)})
+	if len(journal.Entries) != 2 || journal.Position != 2 {
+		t.Errorf("recording should drop the undone entry, got %d at %d", len(journal.Entries), journal.Position)
+	}
+}
+
+func TestJournalUndoNestedCreate(t *testing.T) {
+	dir := t.TempDir()
+	journalFile := varibale.JournalFilea
+	varibale.JournalFilea = filepath.Join(dir, "journal.json")
+	defer func() {
+		varibale.JournalFilea = journalFile
+		journal = &fileJournal{}
+	}()
+	journal = &fileJournal{}
+
+	newDir := filepath.Join(dir, "newdir")
+	if err := os.Mkdir(newDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	recordJournal(journalCreate, []journalItem{newJournalItem("", newDir)})
+	// Creating the file changes the modification time of the directory
+	earlier := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(newDir, earlier, earlier); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(newDir, "file.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	recordJournal(journalCreate, []journalItem{newJournalItem("", file)})
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: dir}}}}
+	m.undoOperation()
+	m.undoOperation()
+	if m.warnModal.open {
+		t.Errorf("undoing both creations should not warn: %s", m.warnModal.content)
+	}
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) || journal.Position != 0 {
+		t.Error("the directory should be removed after the file in it")
+	}
+}
+
+func TestJournalRefusesModifiedTarget(t *testing.T) {
+	dir := t.TempDir()
+	journalFile := varibale.JournalFilea
+	varibale.JournalFilea = filepath.Join(dir, "journal.json")
+	defer func() {
+		varibale.JournalFilea = journalFile
+		journal = &fileJournal{}
+	}()
+	journal = &fileJournal{}
+
+	from := filepath.Join(dir, "a.txt")
+	to := filepath.Join(dir, "sub", "a.txt")
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(to, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recordJournal(journalMove, []journalItem{newJournalItem(from, to)})
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(to, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: dir}}}}
+	m.undoOperation()
+	if !m.warnModal.open || m.warnModal.warnType != noticeWarn {
+		t.Error("undoing a modified file should warn")
+	}
+	if _, err := os.Stat(to); err != nil || journal.Position != 1 {
+		t.Error("the modified file should stay where it is")
+	}
+
+	entry := journalEntry{Operation: journalMove, Items: []journalItem{{From: from, To: to}, {From: from, To: filepath.Join(dir, "b")}}}
+	if entry.description() != "Move 2 items → "+filepath.Join(dir, "sub") {
+		t.Errorf("unexpected description %q", entry.description())
+	}
+}
+
+func TestTrashNameIsReserved(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the trash can with trashinfo files is only used on linux")
+	}
+	dir := t.TempDir()
+	defer func(dataHome string) { basedir.DataHome = dataHome }(basedir.DataHome)
+	basedir.DataHome = filepath.Join(dir, "data")
+	trashDir := filepath.Join(basedir.DataHome, "Trash")
+	for _, name := range []string{"files", "info"} {
+		if err := os.MkdirAll(filepath.Join(trashDir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A dangling symlink and a trashinfo without its file both keep their names
+	if err := os.Symlink("missing", filepath.Join(trashDir, "files", "file.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(trashDir, "info", "file.txt.2.trashinfo"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(src, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	item, err := trashMacOrLinux(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.To != filepath.Join(trashDir, "files", "file.txt.3") || item.TrashInfo != filepath.Join(trashDir, "info", "file.txt.3.trashinfo") {
+		t.Errorf("the file should be trashed as file.txt.3, got %s and %s", item.To, item.TrashInfo)
+	}
+	if data, err := os.ReadFile(item.To); err != nil || string(data) != "a" {
+		t.Errorf("the trashed file should be at %s, got %v", item.To, err)
+	}
+	if info, err := os.ReadFile(item.TrashInfo); err != nil || !strings.Contains(string(info), "Path="+src) {
+		t.Errorf("the trashinfo should point to the original path, got %q", info)
+	}
+	if _, err := os.Lstat(filepath.Join(trashDir, "info", "file.txt.trashinfo")); !os.IsNotExist(err) {
+		t.Error("the trashinfo of a name that is taken should be removed")
+	}
+}
+
+func TestJournalUndoRollsBackPartly(t *testing.T) {
+	dir := t.TempDir()
+	destination := filepath.Join(dir, "destination")
+	if err := os.Mkdir(destination, 0755); err != nil {
+		t.Fatal(err)
+	}
+	items := []journalItem{}
+	for _, name := range []string{"first", "second"} {
+		path := filepath.Join(destination, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, newJournalItem(filepath.Join(dir, "source", name), path))
+	}
+	// Only the first item has its source directory, the second can not be moved back
+	if err := os.Mkdir(filepath.Join(dir, "source"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	items[1].From = filepath.Join(dir, "removed", "second")
+
+	entry := journalEntry{Operation: journalMove, Items: items}
+	if err := entry.undo(); err == nil {
+		t.Fatal("undo should fail for the second item")
+	}
+	for _, name := range []string{"first", "second"} {
+		if _, err := os.Stat(filepath.Join(destination, name)); err != nil {
+			t.Errorf("%s should be back where the move put it, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "source", "first")); !os.IsNotExist(err) {
+		t.Error("the first item should not stay undone")
+	}
+}
@@ -48,6 +48,15 @@ func (m *model) mainKey(msg string, cmd tea.Cmd) ( tea.Cmd) {
 	case containsKey(msg, hotkeys.ChangePanelMode):
 		m.changeFilePanelMode()
 
+	case containsKey(msg, hotkeys.ToggleDetailsView):
+		m.toggleDetailsView()
+
+	case containsKey(msg, hotkeys.ToggleTreeView):
+		m.toggleTreeView()
+
+	case containsKey(msg, hotkeys.ToggleFlatten):
+		m.toggleFlattenView()
+
 	case containsKey(msg, hotkeys.NextFilePanel):
 		m.nextFilePanel()
 
@@ -62,6 +71,18 @@ func (m *model) mainKey(msg string, cmd tea.Cmd) ( tea.Cmd) {
 
 	case containsKey(msg, hotkeys.ToggleFilePreviewPanel):
 		m.toggleFilePreviewPanel()
+
+	case containsKey(msg, hotkeys.ToggleMillerColumns):
+		m.toggleMillerColumns()
+
+	case containsKey(msg, hotkeys.WidenFilePanel):
+		m.resizeFocusedFilePanel(filePanelResizeStep)
+
+	case containsKey(msg, hotkeys.NarrowFilePanel):
+		m.resizeFocusedFilePanel(-filePanelResizeStep)
+
+	case containsKey(msg, hotkeys.ResetLayout):
+		m.resetLayout()
 	
 	case containsKey(msg, hotkeys.FocusOnSidebar):
 		m.focusOnSideBar()
@@ -79,6 +100,12 @@ func (m *model) mainKey(msg string, cmd tea.Cmd) ( tea.Cmd) {
 		go func() {
 			m.pasteItem()
 		}()
+	case containsKey(msg, hotkeys.Undo):
+		m.undoOperation()
+	case containsKey(msg, hotkeys.Redo):
+		m.redoOperation()
+	case containsKey(msg, hotkeys.OpenJournal):
+		m.openJournalModal()
 
 	case containsKey(msg, hotkeys.FilePanelItemCreate):
 		m.panelCreateNewFile()
@@ -88,6 +115,12 @@ func (m *model) mainKey(msg string, cmd tea.Cmd) ( tea.Cmd) {
 	case containsKey(msg, hotkeys.ToggleDotFile):
 		m.toggleDotFileController()
 
+	case containsKey(msg, hotkeys.NextSortType):
+		m.nextSortType()
+
+	case containsKey(msg, hotkeys.ReverseSortOrder):
+		m.reverseSortOrder()
+
 	case containsKey(msg, hotkeys.ExtractFile):
 		go func() {
 			m.extractFile()
@@ -104,6 +137,12 @@ func (m *model) mainKey(msg string, cmd tea.Cmd) ( tea.Cmd) {
 	case containsKey(msg, hotkeys.OpenCommandLine):
 		m.openCommandLine()
 
+	case containsKey(msg, hotkeys.OpenDiskUsage):
+		cmd = m.openDiskUsageModal()
+
+	case containsKey(msg, hotkeys.OpenWorkspaces):
+		m.openWorkspaceModal()
+
 	case containsKey(msg, hotkeys.OpenFileWithEditor):
 		cmd = m.openFileWithEditor()
 
@@ -165,6 +204,30 @@ func (m *model) normalAndBrowserModeKey(msg string) {
 		m.panelItemRename()
 	case containsKey(msg, hotkeys.SearchBar):
 		m.searchBarFocus()
+	case containsKey(msg, hotkeys.RecursiveSearch):
+		m.recursiveSearchBarFocus()
+	case containsKey(msg, hotkeys.ContentSearch):
+		m.openContentSearchModal()
+	case containsKey(msg, hotkeys.FilterBar):
+		m.filterBarFocus()
+	case containsKey(msg, hotkeys.HistoryBack):
+		m.historyBack()
+	case containsKey(msg, hotkeys.HistoryForward):
+		m.historyForward()
+	case containsKey(msg, hotkeys.OpenHistory):
+		m.openHistoryModal()
+	case containsKey(msg, hotkeys.OpenJump):
+		m.openJumpModal()
+	case containsKey(msg, hotkeys.GoToPath):
+		m.openGoToPathModal()
+	case containsKey(msg, hotkeys.TypeAheadFind):
+		m.startTypeAheadFind()
+	case containsKey(msg, hotkeys.SetMark):
+		m.pendingMarkAction = setMarkAction
+	case containsKey(msg, hotkeys.JumpToMark):
+		m.pendingMarkAction = jumpToMarkAction
+	case containsKey(msg, hotkeys.OpenMarks):
+		m.openMarksModal()
 	}
 }
 
@@ -173,7 +236,133 @@ func (m *model)  typingModalOpenKey(msg string) {
 	case containsKey(msg, hotkeys.CancelTyping):
 		m.cancelTypingModal()
 	case containsKey(msg, hotkeys.ConfirmTyping):
-		m.createItem()
+		if m.typingModal.typingType == goToPathTyping {
+			m.confirmGoToPath()
+		} else {
+			m.createItem()
+		}
+	case "tab":
+		if m.typingModal.typingType == goToPathTyping {
+			m.completeGoToPath()
+		}
+	}
+}
+
+func (m *model) contentSearchModalKey(msg string) tea.Cmd {
+	switch msg {
+	case containsKey(msg, hotkeys.CancelTyping):
+		m.closeContentSearchModal()
+	case containsKey(msg, hotkeys.ConfirmTyping):
+		return m.confirmContentSearch()
+	case "up":
+		m.contentSearchModalListUp()
+	case "down":
+		m.contentSearchModalListDown()
+	}
+	return nil
+}
+
+func (m *model) diskUsageModalKey(msg string) tea.Cmd {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping):
+		m.closeDiskUsageModal()
+	case containsKey(msg, hotkeys.ListUp):
+		m.diskUsageModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.diskUsageModalListDown()
+	case containsKey(msg, hotkeys.Confirm):
+		return m.diskUsageEnterDirectory()
+	case containsKey(msg, hotkeys.ParentDirectory):
+		return m.diskUsageParentDirectory()
+	case containsKey(msg, hotkeys.DeleteItems):
+		m.diskUsageDelete()
+	case containsKey(msg, hotkeys.OpenDiskUsage):
+		return m.diskUsageRescan()
+	}
+	return nil
+}
+
+func (m *model) historyModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping), containsKey(msg, hotkeys.OpenHistory):
+		m.closeHistoryModal()
+	case containsKey(msg, hotkeys.ListUp):
+		m.historyModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.historyModalListDown()
+	case containsKey(msg, hotkeys.Confirm):
+		m.confirmHistoryModal()
+	}
+}
+
+func (m *model) pasteConflictModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping):
+		m.decidePasteConflict(skipConflict)
+	case containsKey(msg, hotkeys.ListUp):
+		m.pasteConflictModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.pasteConflictModalListDown()
+	case containsKey(msg, hotkeys.Confirm):
+		m.confirmPasteConflict()
+	case "a":
+		m.pasteConflictModal.applyToAll = !m.pasteConflictModal.applyToAll
+	}
+}
+
+func (m *model) journalModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping), containsKey(msg, hotkeys.OpenJournal):
+		m.closeJournalModal()
+	case containsKey(msg, hotkeys.ListUp):
+		m.journalModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.journalModalListDown()
+	case containsKey(msg, hotkeys.Undo):
+		m.undoOperation()
+	case containsKey(msg, hotkeys.Redo):
+		m.redoOperation()
+	}
+}
+
+func (m *model) jumpModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.CancelTyping):
+		m.closeJumpModal()
+	case containsKey(msg, hotkeys.ConfirmTyping):
+		m.confirmJumpModal()
+	case "up":
+		m.jumpModalListUp()
+	case "down":
+		m.jumpModalListDown()
+	}
+}
+
+func (m *model) marksModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping), containsKey(msg, hotkeys.OpenMarks):
+		m.closeMarksModal()
+	case containsKey(msg, hotkeys.ListUp):
+		m.marksModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.marksModalListDown()
+	case containsKey(msg, hotkeys.Confirm):
+		m.confirmMarksModal()
+	case containsKey(msg, hotkeys.DeleteItems):
+		m.deleteSelectedMark()
+	}
+}
+
+func (m *model) workspaceModalKey(msg string) {
+	switch msg {
+	case containsKey(msg, hotkeys.Quit), containsKey(msg, hotkeys.CancelTyping), containsKey(msg, hotkeys.OpenWorkspaces):
+		m.closeWorkspaceModal()
+	case containsKey(msg, hotkeys.ListUp):
+		m.workspaceModalListUp()
+	case containsKey(msg, hotkeys.ListDown):
+		m.workspaceModalListDown()
+	case containsKey(msg, hotkeys.Confirm):
+		m.confirmWorkspaceModal()
 	}
 }
 
@@ -183,6 +372,15 @@ func (m *model) warnModalOpenKey(msg string) {
 		m.cancelWarnModal()
 	case containsKey(msg, hotkeys.Confirm):
 		m.warnModal.open = false
+		// A notice only has to be closed
+		if m.warnModal.warnType != confirmDeleteItem {
+			return
+		}
+		// The disk usage analyzer asks to delete its own entry
+		if m.diskUsageModal.open {
+			m.confirmDiskUsageDelete()
+			return
+		}
 		panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 		if m.fileModel.filePanels[m.filePanelFocusIndex].panelMode == selectMode {
 			if isExternalDiskPath(panel.location) {
@@ -0,0 +1,299 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+// The narrowest a file panel and the file preview can be resized to
+const (
+	minimumFilePanelWidth   = 18
+	minimumFilePreviewWidth = 10
+)
+
+// Columns a file panel grows or shrinks by with the resize hotkeys
+const filePanelResizeStep = 4
+
+// Relative widths of the file panels, by position, and of the file preview. A missing or zero
+// ratio is the default: every file panel is as wide as the others and the file preview follows
+// file_preview_width
+type layoutRatios struct {
+	FilePanels  []float64 `json:"file_panels,omitempty"`
+	FilePreview float64   `json:"file_preview,omitempty"`
+}
+
+// A border being dragged with the mouse, the right border of the file panel
+type borderDrag struct {
+	active bool
+	panel  int
+}
+
+// Load the ratios the panels were last resized to
+func loadLayoutRatios() layoutRatios {
+	ratios := layoutRatios{}
+	jsonData, err := os.ReadFile(varibale.LayoutFilea)
+	if err != nil {
+		outPutLog("Load layout ratios function read superfile data error", err)
+		return ratios
+	}
+
+	if len(jsonData) == 0 {
+		return ratios
+	}
+
+	err = json.Unmarshal(jsonData, &ratios)
+	if err != nil {
+		outPutLog("Load layout ratios function unmarshal superfile data error", err)
+	}
+	return ratios
+}
+
+// Save the ratios so the next superfile opens with the same widths
+func saveLayoutRatios(ratios layoutRatios) {
+	updatedData, err := json.Marshal(ratios)
+	if err != nil {
+		outPutLog("Save layout ratios function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.LayoutFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save layout ratios function write superfile data error", err)
+	}
+}
+
+// Return the ratio of the file panel at the position
+func (ratios layoutRatios) filePanel(index int) float64 {
+	if index < len(ratios.FilePanels) && ratios.FilePanels[index] > 0 {
+		return ratios.FilePanels[index]
+	}
+	return 1
+}
+
+// Return the ratio of the file preview next to the file panels with the ratios
+func (ratios layoutRatios) filePreview(filePanelRatios []float64) float64 {
+	if ratios.FilePreview > 0 {
+		return ratios.FilePreview
+	}
+	if Config.FilePreviewWidth == 0 {
+		return 1
+	}
+	// The file preview takes one file_preview_width-th of the width
+	total := 0.0
+	for _, ratio := range filePanelRatios {
+		total += ratio
+	}
+	return total / float64(Config.FilePreviewWidth-1)
+}
+
+// Split the width between the ratios, the last one gets what is left after rounding
+func splitWidth(width int, ratios []float64) []int {
+	total := 0.0
+	for _, ratio := range ratios {
+		total += ratio
+	}
+	widths := make([]int, len(ratios))
+	left := width
+	for i, ratio := range ratios {
+		if i == len(ratios)-1 {
+			widths[i] = left
+			break
+		}
+		widths[i] = int(float64(width) * ratio / total)
+		left -= widths[i]
+	}
+	return widths
+}
+
+// Return the width the sidebar takes with its border
+func sidebarOuterWidth() int {
+	if Config.SidebarWidth == 0 {
+		return 0
+	}
+	return Config.SidebarWidth + 2
+}
+
+// Return whether the file preview is below the file panels instead of next to them
+func (m model) filePreviewStacked() bool {
+	return m.fileModel.filePreview.open && !m.fileModel.millerColumns &&
+		Config.StackFilePreviewWidth > 0 && m.fullWidth < Config.StackFilePreviewWidth
+}
+
+// Compute the size of the file panels and the file preview from the terminal size and the ratios
+func (m *model) applyLayout() {
+	mainHeight := m.fullHeight - footerHeight + 1
+	preview := &m.fileModel.filePreview
+	preview.stacked = m.filePreviewStacked()
+	m.mainPanelHeight = mainHeight
+	preview.height = mainHeight + 2
+	if preview.stacked {
+		// The file panels and the file preview share the height, with a border around the file panels
+		preview.height = (mainHeight + 2) / 2
+		m.mainPanelHeight = mainHeight - preview.height
+	}
+
+	ratios := []float64{}
+	for i := range m.fileModel.filePanels {
+		ratios = append(ratios, m.fileModel.ratios.filePanel(i))
+	}
+	sideBySide := preview.open && !preview.stacked
+	if sideBySide {
+		ratios = append(ratios, m.fileModel.ratios.filePreview(ratios[:len(m.fileModel.filePanels)]))
+	}
+
+	// Every file panel has a border, the file preview does not
+	widths := splitWidth(m.fullWidth-sidebarOuterWidth()-2*len(m.fileModel.filePanels), ratios)
+	for i := range m.fileModel.filePanels {
+		m.fileModel.filePanels[i].width = widths[i]
+		m.fileModel.filePanels[i].searchBar.Width = widths[i] - 4
+	}
+	preview.width = 0
+	if sideBySide {
+		preview.width = widths[len(widths)-1]
+	} else if preview.stacked {
+		preview.width = m.fullWidth - sidebarOuterWidth()
+	}
+
+	previewWidth := preview.width
+	if preview.stacked {
+		previewWidth = 0
+	}
+	m.fileModel.maxFilePanel = min(10, (m.fullWidth-Config.SidebarWidth-previewWidth)/20)
+}
+
+// Render the sidebar as high as the file panels and the file preview below them
+func (m model) stackedSidebarRender() string {
+	m.mainPanelHeight += m.fileModel.filePreview.height
+	return m.sidebarRender()
+}
+
+// Return the width of the narrowest file panel
+func (m model) narrowestFilePanelWidth() int {
+	width := m.fileModel.filePanels[0].width
+	for _, panel := range m.fileModel.filePanels {
+		width = min(width, panel.width)
+	}
+	return width
+}
+
+// Return the column the file panel starts at
+func (m model) filePanelX(index int) int {
+	x := sidebarOuterWidth()
+	for i := 0; i < index; i++ {
+		x += m.fileModel.filePanels[i].width + 2
+	}
+	return x
+}
+
+// Move the right border of the file panel by delta columns, the panel or file preview on the
+// right gives or takes the columns. Returns false when either would get too narrow
+func (m *model) moveFilePanelBorder(index int, delta int) bool {
+	panels := m.fileModel.filePanels
+	sideBySide := m.fileModel.filePreview.open && !m.fileModel.filePreview.stacked
+	if index == len(panels)-1 && !sideBySide {
+		return false
+	}
+
+	// The current widths become the ratios, so only the two neighbours change
+	ratios := layoutRatios{}
+	for _, panel := range panels {
+		ratios.FilePanels = append(ratios.FilePanels, float64(panel.width))
+	}
+	ratios.FilePanels[index] += float64(delta)
+	if index == len(panels)-1 {
+		ratios.FilePreview = float64(m.fileModel.filePreview.width - delta)
+		if ratios.FilePreview < minimumFilePreviewWidth {
+			return false
+		}
+	} else {
+		ratios.FilePanels[index+1] -= float64(delta)
+		if sideBySide {
+			ratios.FilePreview = float64(m.fileModel.filePreview.width)
+		}
+	}
+	total := 0.0
+	for _, ratio := range ratios.FilePanels {
+		if ratio < minimumFilePanelWidth {
+			return false
+		}
+		total += ratio
+	}
+
+	// Scale the ratios so a file panel of average width is 1, the default of new file panels
+	average := total / float64(len(panels))
+	for i := range ratios.FilePanels {
+		ratios.FilePanels[i] /= average
+	}
+	ratios.FilePreview /= average
+
+	// Keep the ratios of file panels that are closed now, they apply again when reopened
+	if len(m.fileModel.ratios.FilePanels) > len(panels) {
+		ratios.FilePanels = append(ratios.FilePanels, m.fileModel.ratios.FilePanels[len(panels):]...)
+	}
+	m.fileModel.ratios = ratios
+	m.applyLayout()
+	return true
+}
+
+// Widen the focused file panel, or narrow it with a negative step. The last file panel moves
+// its left border when there is no file preview on its right
+func (m *model) resizeFocusedFilePanel(step int) {
+	index := m.filePanelFocusIndex
+	sideBySide := m.fileModel.filePreview.open && !m.fileModel.filePreview.stacked
+	if index == len(m.fileModel.filePanels)-1 && !sideBySide {
+		if index == 0 || !m.moveFilePanelBorder(index-1, -step) {
+			return
+		}
+	} else if !m.moveFilePanelBorder(index, step) {
+		return
+	}
+	saveLayoutRatios(m.fileModel.ratios)
+}
+
+// Go back to panels of equal width and the file preview of file_preview_width
+func (m *model) resetLayout() {
+	m.fileModel.ratios = layoutRatios{}
+	m.applyLayout()
+	saveLayoutRatios(m.fileModel.ratios)
+}
+
+// Return the file panel whose right border is at the column, or -1
+func (m model) filePanelBorderAt(x int, y int) int {
+	if m.fileModel.millerColumns || y >= m.mainPanelHeight+2 {
+		return -1
+	}
+	// The right border of the last file panel is the edge of the screen without the file preview
+	sideBySide := m.fileModel.filePreview.open && !m.fileModel.filePreview.stacked
+	for i, panel := range m.fileModel.filePanels {
+		if x == m.filePanelX(i)+panel.width+1 && (i < len(m.fileModel.filePanels)-1 || sideBySide) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Start dragging the border under the mouse, returns false when there is none
+func (m *model) startBorderDrag(x int, y int) bool {
+	index := m.filePanelBorderAt(x, y)
+	if index < 0 || m.mouseBlocked() {
+		return false
+	}
+	m.borderDrag = borderDrag{active: true, panel: index}
+	return true
+}
+
+// Move the dragged border to the column of the mouse
+func (m *model) moveBorderDrag(x int) {
+	border := m.filePanelX(m.borderDrag.panel) + m.fileModel.filePanels[m.borderDrag.panel].width + 1
+	if x != border {
+		m.moveFilePanelBorder(m.borderDrag.panel, x-border)
+	}
+}
+
+// Stop dragging the border and keep the new widths
+func (m *model) endBorderDrag() {
+	m.borderDrag = borderDrag{}
+	saveLayoutRatios(m.fileModel.ratios)
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestLayout(t *testing.T) {
+	defer func(saved ConfigType) { Config = saved }(Config)
+	layoutFile := varibale.LayoutFilea
+	varibale.LayoutFilea = filepath.Join(t.TempDir(), "layout.json")
+	defer func() { varibale.LayoutFilea = layoutFile }()
+	Config.SidebarWidth = 20
+	Config.FilePreviewWidth = 0
+
+	newModel := func(panels int, previewOpen bool) model {
+		m := model{fullWidth: 150, fullHeight: 50, fileModel: fileModel{filePreview: filePreviewPanel{open: previewOpen}}}
+		for i := 0; i < panels; i++ {
+			m.fileModel.filePanels = append(m.fileModel.filePanels, filePanel{})
+		}
+		m.applyLayout()
+		return m
+	}
+	mainWidth := func(m model) int {
+		width := sidebarOuterWidth() + m.fileModel.filePreview.width
+		for _, panel := range m.fileModel.filePanels {
+			width += panel.width + 2
+		}
+		return width
+	}
+
+	for _, panels := range []int{1, 2, 3} {
+		for _, previewOpen := range []bool{true, false} {
+			if m := newModel(panels, previewOpen); mainWidth(m) != m.fullWidth {
+				t.Errorf("%d panels with preview %v should fill the screen, got %d", panels, previewOpen, mainWidth(m))
+			}
+		}
+	}
+	Config.FilePreviewWidth = 3
+	if m := newModel(2, true); m.fileModel.filePreview.width < (m.fullWidth-sidebarOuterWidth()-4)/3 || m.fileModel.filePreview.width > (m.fullWidth-sidebarOuterWidth()-4)/3+1 {
+		t.Errorf("the file preview should take a third, got %d", m.fileModel.filePreview.width)
+	}
+	Config.FilePreviewWidth = 0
+
+	// Moving a border only changes its two neighbours
+	m := newModel(2, true)
+	widths := []int{m.fileModel.filePanels[0].width, m.fileModel.filePanels[1].width, m.fileModel.filePreview.width}
+	if !m.moveFilePanelBorder(0, 5) {
+		t.Fatal("the border should move")
+	}
+	if m.fileModel.filePanels[0].width != widths[0]+5 || m.fileModel.filePanels[1].width != widths[1]-5 || m.fileModel.filePreview.width != widths[2] {
+		t.Errorf("only the first two panels should change, got %d %d %d", m.fileModel.filePanels[0].width, m.fileModel.filePanels[1].width, m.fileModel.filePreview.width)
+	}
+	if m.moveFilePanelBorder(0, 100) {
+		t.Error("the border should not make a file panel narrower than the minimum")
+	}
+	if border := m.filePanelBorderAt(m.filePanelX(1)-1, 10); border != 0 {
+		t.Errorf("the column left of the second panel should be the border of the first, got %d", border)
+	}
+
+	// The ratios are kept, and a new file panel is as wide as an average one
+	m.resizeFocusedFilePanel(filePanelResizeStep)
+	loaded := loadLayoutRatios()
+	if len(loaded.FilePanels) != 2 || loaded.FilePanels[0] <= loaded.FilePanels[1] {
+		t.Errorf("the widened first panel should be saved, got %v", loaded)
+	}
+	m.fileModel.filePanels = append(m.fileModel.filePanels, filePanel{})
+	m.applyLayout()
+	if average := (m.fileModel.filePanels[0].width + m.fileModel.filePanels[1].width) / 2; m.fileModel.filePanels[2].width < average-1 || m.fileModel.filePanels[2].width > average+1 {
+		t.Errorf("a new file panel should be as wide as an average one (%d), got %d", average, m.fileModel.filePanels[2].width)
+	}
+
+	// Narrow terminals get the file preview below the file panels
+	Config.StackFilePreviewWidth = 200
+	m = newModel(2, true)
+	if !m.fileModel.filePreview.stacked || m.fileModel.filePreview.width != m.fullWidth-sidebarOuterWidth() {
+		t.Errorf("the file preview should be stacked over the whole width, got %+v", m.fileModel.filePreview)
+	}
+	if m.mainPanelHeight+2+m.fileModel.filePreview.height != m.fullHeight-footerHeight+3 {
+		t.Errorf("the file panels and the file preview should share the height, got %d and %d", m.mainPanelHeight, m.fileModel.filePreview.height)
+	}
+	if target, _ := m.mouseTargetAt(60, m.mainPanelHeight+3); target != noMouseTarget {
+		t.Errorf("the stacked file preview should not be a file panel, got %d", target)
+	}
+}
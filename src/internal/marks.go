@@ -0,0 +1,259 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+	varibale "github.com/yorukot/superfile/src/config"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// A location saved with a mark, and the file the cursor was on
+type mark struct {
+	Location string `json:"location"`
+	File     string `json:"file"`
+}
+
+var (
+	// Uppercase marks, saved in marks.json
+	globalMarks = map[string]mark{}
+	// Lowercase marks, forgotten when superfile is closed
+	sessionMarks = map[string]mark{}
+)
+
+// Load the saved global marks
+func loadMarks() {
+	jsonData, err := os.ReadFile(varibale.MarksFilea)
+	if err != nil {
+		outPutLog("Load marks function read superfile data error", err)
+		return
+	}
+
+	if len(jsonData) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(jsonData, &globalMarks)
+	if err != nil {
+		outPutLog("Load marks function unmarshal superfile data error", err)
+	}
+}
+
+// Save the global marks
+func saveMarks() {
+	updatedData, err := json.Marshal(globalMarks)
+	if err != nil {
+		outPutLog("Save marks function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.MarksFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save marks function write superfile data error", err)
+	}
+}
+
+// Return the letter of the key when it can be used as a mark
+func markLetter(key string) (string, bool) {
+	runes := []rune(key)
+	if len(runes) != 1 || runes[0] > unicode.MaxASCII || !unicode.IsLetter(runes[0]) {
+		return "", false
+	}
+	return key, true
+}
+
+// Return the marks of the letter, uppercase letters are global marks
+func marksOf(letter string) map[string]mark {
+	if unicode.IsUpper([]rune(letter)[0]) {
+		return globalMarks
+	}
+	return sessionMarks
+}
+
+// Save the location and the cursor file of the focused file panel with the letter
+func (m *model) setMark(letter string) {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	newMark := mark{Location: panel.location}
+	if len(panel.element) > 0 {
+		newMark.File = panel.element[panel.cursor].location
+	}
+
+	marksOf(letter)[letter] = newMark
+	if unicode.IsUpper([]rune(letter)[0]) {
+		saveMarks()
+	}
+}
+
+// Go to the location of the mark in the focused file panel and put the cursor on its file
+func (m *model) jumpToMark(letter string) {
+	savedMark, ok := marksOf(letter)[letter]
+	if !ok {
+		return
+	}
+	if info, err := os.Stat(savedMark.Location); err != nil || !info.IsDir() {
+		outPutLog("Jump to mark function mark location error", letter, savedMark.Location, err)
+		return
+	}
+
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	panel.directoryRecord[panel.location] = directoryRecord{
+		directoryCursor: panel.cursor,
+		directoryRender: panel.render,
+		expanded:        panel.expanded(panel.location),
+	}
+	panel.location = savedMark.Location
+	panel.targetFile = savedMark.File
+	panel.cursor = 0
+	panel.render = 0
+	panel.searchBar.SetValue("")
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Handle the letter typed after the set mark or jump to mark hotkey
+func (m *model) pendingMarkKey(msg string) {
+	action := m.pendingMarkAction
+	m.pendingMarkAction = noMarkAction
+
+	letter, ok := markLetter(msg)
+	if !ok {
+		return
+	}
+	if action == setMarkAction {
+		m.setMark(letter)
+	} else {
+		m.jumpToMark(letter)
+	}
+}
+
+// Return the letters of all marks, global marks first
+func markLetters() []string {
+	letters := []string{}
+	for letter := range globalMarks {
+		letters = append(letters, letter)
+	}
+	for letter := range sessionMarks {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i] < letters[j]
+	})
+	return letters
+}
+
+func (m *model) openMarksModal() {
+	m.marksModal = marksModal{open: true}
+}
+
+func (m *model) closeMarksModal() {
+	m.marksModal = marksModal{}
+}
+
+// Go to the mark selected in the marks popup
+func (m *model) confirmMarksModal() {
+	letters := markLetters()
+	if len(letters) == 0 {
+		return
+	}
+	letter := letters[m.marksModal.cursor]
+	m.closeMarksModal()
+	m.jumpToMark(letter)
+}
+
+// Delete the mark selected in the marks popup
+func (m *model) deleteSelectedMark() {
+	letters := markLetters()
+	if len(letters) == 0 {
+		return
+	}
+	letter := letters[m.marksModal.cursor]
+	delete(marksOf(letter), letter)
+	if unicode.IsUpper([]rune(letter)[0]) {
+		saveMarks()
+	}
+
+	if m.marksModal.cursor >= len(letters)-1 {
+		m.marksModal.cursor = max(0, len(letters)-2)
+	}
+	if m.marksModal.renderIndex > m.marksModal.cursor {
+		m.marksModal.renderIndex = m.marksModal.cursor
+	}
+}
+
+// Marks modal list up
+func (m *model) marksModalListUp() {
+	length := len(markLetters())
+	if m.marksModal.cursor > 0 {
+		m.marksModal.cursor--
+		if m.marksModal.cursor < m.marksModal.renderIndex {
+			m.marksModal.renderIndex--
+		}
+	} else if length > 0 {
+		m.marksModal.cursor = length - 1
+		m.marksModal.renderIndex = max(0, length-marksModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Marks modal list down
+func (m *model) marksModalListDown() {
+	length := len(markLetters())
+	if m.marksModal.cursor < length-1 {
+		m.marksModal.cursor++
+		if m.marksModal.cursor >= m.marksModal.renderIndex+marksModalListHeight(m.helpMenu.height) {
+			m.marksModal.renderIndex++
+		}
+	} else {
+		m.marksModal.cursor = 0
+		m.marksModal.renderIndex = 0
+	}
+}
+
+// Lines of the marks modal left for the marks
+func marksModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the marks popup
+func (m model) marksModalRender() string {
+	width := m.helpMenu.width
+	letters := markLetters()
+
+	content := " " + filePanelTopDirectoryIconStyle.Render(icon.Directory+icon.Space) + filePanelTopPathStyle.Render("Marks") + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	if len(letters) == 0 {
+		content += modalStyle.Render(" " + icon.Error + "  No marks yet")
+	}
+	for i := m.marksModal.renderIndex; i < m.marksModal.renderIndex+marksModalListHeight(m.helpMenu.height) && i < len(letters); i++ {
+		if i != m.marksModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.marksModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		savedMark := marksOf(letters[i])[letters[i]]
+		location := savedMark.Location
+		if savedMark.File != "" {
+			location = savedMark.File
+		}
+		if ansi.StringWidth(location) > width-5 {
+			location = truncateTextBeginning(location, width-5, "...")
+		}
+		content += cursor + helpMenuHotkeyStyle.Render(letters[i]+" ") + modalStyle.Render(location)
+	}
+
+	count := "0/0"
+	if len(letters) > 0 {
+		count = fmt.Sprintf("%d/%d", m.marksModal.cursor+1, len(letters))
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestMarks(t *testing.T) {
+	dir := t.TempDir()
+	marksFile := varibale.MarksFilea
+	varibale.MarksFilea = filepath.Join(dir, "marks.json")
+	defer func() { varibale.MarksFilea = marksFile }()
+	for _, name := range []string{"a/file.txt", "b/other.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{
+		location:        filepath.Join(dir, "a"),
+		element:         []element{{name: "file.txt", location: filepath.Join(dir, "a/file.txt")}},
+		directoryRecord: map[string]directoryRecord{},
+	}}}}
+	m.pendingMarkAction = setMarkAction
+	m.pendingMarkKey("A")
+	m.pendingMarkAction = setMarkAction
+	m.pendingMarkKey("a")
+
+	m.fileModel.filePanels[0].location = filepath.Join(dir, "b")
+	m.pendingMarkAction = jumpToMarkAction
+	m.pendingMarkKey("A")
+	panel := m.fileModel.filePanels[0]
+	if panel.location != filepath.Join(dir, "a") || panel.targetFile != filepath.Join(dir, "a/file.txt") {
+		t.Errorf("jump should go to a and target file.txt, got %s and %s", panel.location, panel.targetFile)
+	}
+	if m.pendingMarkAction != noMarkAction {
+		t.Error("the mark action should be done after the letter")
+	}
+
+	// Only uppercase marks are saved
+	globalMarks = map[string]mark{}
+	sessionMarks = map[string]mark{}
+	loadMarks()
+	if _, ok := globalMarks["A"]; !ok || len(globalMarks) != 1 {
+		t.Errorf("saved marks should only be A: %v", globalMarks)
+	}
+
+	if _, ok := markLetter("1"); ok {
+		t.Error("digits should not be mark letters")
+	}
+}
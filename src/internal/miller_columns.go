@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// Switch between the file panels side by side and the miller columns layout: the parent
+// directory, the focused file panel and the file preview
+func (m *model) toggleMillerColumns() {
+	m.fileModel.millerColumns = !m.fileModel.millerColumns
+	m.applyLayout()
+}
+
+// Return the widths of the parent column, the focused file panel and the file preview in the
+// miller columns layout, computed like a single file panel next to the parent column
+func (m model) millerColumnsWidths() (parentWidth int, panelWidth int, previewWidth int) {
+	parentWidth = max(10, (m.fullWidth-sidebarOuterWidth())/6)
+	// Both the parent column and the file panel have a border
+	width := m.fullWidth - sidebarOuterWidth() - parentWidth - 4
+	if m.fileModel.filePreview.open {
+		if Config.FilePreviewWidth == 0 {
+			previewWidth = width / 2
+		} else {
+			previewWidth = (m.fullWidth - sidebarOuterWidth()) / Config.FilePreviewWidth
+		}
+	}
+	panelWidth = width - previewWidth
+	return parentWidth, panelWidth, previewWidth
+}
+
+// Render the parent column, the focused file panel and the file preview
+func (m model) millerColumnsRender() string {
+	parentWidth, panelWidth, previewWidth := m.millerColumnsWidths()
+
+	// Render the focused file panel and the preview as if it was the only file panel on a
+	// screen without the parent column
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	panel.width = panelWidth
+	panel.searchBar.Width = panelWidth - 4
+	columns := m
+	columns.fullWidth -= parentWidth + 2
+	columns.fileModel.filePanels = []filePanel{panel}
+	columns.filePanelFocusIndex = 0
+	columns.fileModel.filePreview.width = previewWidth
+
+	filePanel := columns.filePanelRender()
+	filePreview := ""
+	if m.fileModel.filePreview.open {
+		filePreview = columns.filePreviewPanelRender()
+	}
+	return lipgloss.JoinHorizontal(0, m.millerParentColumnRender(parentWidth), filePanel, filePreview)
+}
+
+// Render the entries of the parent directory of the focused file panel, with the current
+// directory highlighted
+func (m model) millerParentColumnRender(width int) string {
+	location := m.fileModel.filePanels[m.filePanelFocusIndex].location
+	parent := filepath.Dir(location)
+
+	content := filePanelTopDirectoryIconStyle.Render(" "+icon.Directory+icon.Space) + filePanelTopPathStyle.Render(truncateTextBeginning(parent, width-4, "...")) + "\n"
+	content += filePanelDividerStyle(noneFocus).Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	entries := []os.DirEntry{}
+	if parent != location {
+		var err error
+		entries, err = millerParentEntries(parent, m.toggleDotFile)
+		if err != nil {
+			outPutLog("Miller parent column render function read directory error", err)
+		}
+	}
+	if len(entries) == 0 {
+		content += filePanelStyle.Render(" " + icon.Error + "  No parent directory")
+		bottomBorder := generateFooterBorder("0/0", width+7)
+		return filePanelBorderStyle(m.mainPanelHeight, width, noneFocus, bottomBorder).Render(content)
+	}
+
+	current := 0
+	for i, entry := range entries {
+		if entry.Name() == filepath.Base(location) {
+			current = i
+		}
+	}
+	height := m.mainPanelHeight - 2
+	// Keep the current directory in the middle of the column
+	render := max(0, min(current-height/2, len(entries)-height))
+	for i := render; i < render+height && i < len(entries); i++ {
+		if i != render {
+			content += "\n"
+		}
+		cursor := " "
+		if i == current {
+			cursor = icon.Cursor
+		}
+		content += filePanelCursorStyle.Render(cursor) + prettierName(entries[i].Name(), width-5, entries[i].IsDir(), i == current, filePanelBGColor)
+	}
+
+	bottomBorder := generateFooterBorder(fmt.Sprintf("%d/%d", current+1, len(entries)), width+7)
+	return filePanelBorderStyle(m.mainPanelHeight, width, noneFocus, bottomBorder).Render(content)
+}
+
+// Return the entries of the directory sorted like the directory preview, directories first
+func millerParentEntries(location string, displayDotFile bool) ([]os.DirEntry, error) {
+	files, err := os.ReadDir(location)
+	if err != nil {
+		return nil, err
+	}
+	entries := []os.DirEntry{}
+	for _, file := range files {
+		if displayDotFile || !strings.HasPrefix(file.Name(), ".") {
+			entries = append(entries, file)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
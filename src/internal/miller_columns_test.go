@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestMillerColumns(t *testing.T) {
+	defer func(saved ConfigType) { Config = saved }(Config)
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	Config.SidebarWidth = 20
+	Config.FilePreviewWidth = 0
+	hotkeys.SearchBar = []string{"/"}
+	dir := t.TempDir()
+	// Going to the parent directory records a visit, it must not reach the real frecency database
+	frecencyFile := varibale.FrecencyFilea
+	varibale.FrecencyFilea = filepath.Join(t.TempDir(), "frecency.json")
+	defer func(database map[string]frecencyEntry) {
+		flushFrecencyDatabase()
+		varibale.FrecencyFilea = frecencyFile
+		frecencyDatabase = database
+	}(frecencyDatabase)
+	frecencyDatabase = map[string]frecencyEntry{}
+	for _, name := range []string{"alpha", "beta", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := model{
+		fullWidth:       160,
+		mainPanelHeight: 30,
+		fileModel: fileModel{
+			millerColumns: true,
+			filePreview:   filePreviewPanel{open: true},
+			filePanels: []filePanel{
+				{location: dir, searchBar: generateSearchBar()},
+				{location: filepath.Join(dir, "beta"), focusType: focus, searchBar: generateSearchBar(), directoryRecord: map[string]directoryRecord{}},
+			},
+		},
+		filePanelFocusIndex: 1,
+	}
+
+	parentWidth, panelWidth, _ := m.millerColumnsWidths()
+	if width := lipgloss.Width(m.millerColumnsRender()); width != m.fullWidth-Config.SidebarWidth-2 {
+		t.Errorf("the columns should fill the screen next to the sidebar, got %d", width)
+	}
+	if target, panel := m.mouseTargetAt(Config.SidebarWidth+2+parentWidth+2, 10); target != filePanelMouseTarget || panel != 1 {
+		t.Errorf("the middle column should be the focused file panel, got %d %d", target, panel)
+	}
+	if target, _ := m.mouseTargetAt(Config.SidebarWidth+2+parentWidth+panelWidth+5, 10); target != noMouseTarget {
+		t.Errorf("the file preview should not be a file panel, got %d", target)
+	}
+
+	entries, err := millerParentEntries(dir, false)
+	if err != nil || len(entries) != 2 || entries[1].Name() != "beta" {
+		t.Errorf("the parent column should list alpha and beta without dotfiles, got %v %v", entries, err)
+	}
+
+	// Going up slides the columns and keeps the directory we left under the cursor
+	m.parentDirectory()
+	panel := m.fileModel.filePanels[1]
+	if panel.location != dir || panel.targetFile != filepath.Join(dir, "beta") {
+		t.Errorf("going up should target beta in %s, got %s in %s", dir, panel.targetFile, panel.location)
+	}
+}
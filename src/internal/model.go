@@ -1,10 +1,11 @@
 package internal
 
 import (
+	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/barasher/go-exiftool"
@@ -16,6 +17,8 @@ import (
 )
 
 var LastTimeCursorMove = [2]int{int(time.Now().UnixMicro()), 0}
+// Metadata of clicks and key presses is read in goroutines that all set LastTimeCursorMove
+var lastTimeCursorMoveMutex sync.Mutex
 var ListeningMessage = true
 
 var firstUse = false
@@ -33,7 +36,20 @@ var progressBarLastRenderTime time.Time = time.Now()
 func InitialModel(dir string, firstUseCheck bool) model {
 	toggleDotFileBool, firstFilePanelDir := initialConfig(dir)
 	firstUse = firstUseCheck
-	return defaultModelConfig(toggleDotFileBool, firstFilePanelDir)
+	m := defaultModelConfig(toggleDotFileBool, firstFilePanelDir)
+	// A path given on the command line wins over the restore_session config, but not over --restore
+	if varibale.OpenWorkspace != "" {
+		ws, ok := findWorkspace(loadWorkspaces(), varibale.OpenWorkspace)
+		if !ok {
+			log.Fatalf("Workspace %q is not defined in %s", varibale.OpenWorkspace, varibale.WorkspacesFilea)
+		}
+		m.switchWorkspace(ws)
+	} else if varibale.RestoreSession || (Config.RestoreSession && dir == "") {
+		if saved, ok := loadSession(); ok {
+			m.restoreSession(saved)
+		}
+	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
@@ -46,6 +62,7 @@ func (m model) Init() tea.Cmd {
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	var backgroundLoadCmd tea.Cmd
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 	switch msg := msg.(type) {
 	case channelMessage:
@@ -53,33 +70,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.warnModal = msg.warnModal
 		} else if msg.messageType == sendMetadata {
 			m.fileMetaData.metaData = msg.metadata
+		} else if msg.messageType == sendDirectoryChange {
+			m.refreshFilePanelsWithLocation(msg.location)
+			gitStatus.invalidate(msg.location)
+			diskUsage.invalidate(msg.location)
+		} else if msg.messageType == sendGitStatus {
+			// Nothing to update, the file panels are rendered again with the new git status
+		} else if msg.messageType == sendPasteConflict {
+			m.openPasteConflict(msg.pasteConflict)
 		} else {
 			if !arrayContains(m.processBarModel.processList, msg.messageId) {
 				m.processBarModel.processList = append(m.processBarModel.processList, msg.messageId)
 			}
 			m.processBarModel.process[msg.messageId] = msg.processNewState
 		}
+	case directoryLoadMsg:
+		backgroundLoadCmd = m.handleDirectoryLoad(msg)
+	case recursiveSearchMsg:
+		backgroundLoadCmd = m.handleRecursiveSearch(msg)
+	case contentSearchMsg:
+		backgroundLoadCmd = m.handleContentSearch(msg)
+	case diskUsageMsg:
+		backgroundLoadCmd = m.handleDiskUsageScan(msg)
+	case typeAheadFindTimeoutMsg:
+		m.handleTypeAheadFindTimeout(msg)
 	case tea.WindowSizeMsg:
 		m.fullHeight = msg.Height
 		m.fullWidth = msg.Width
 
-		if m.fileModel.filePreview.open {
-			// File preview panel width same as file panel
-			if Config.FilePreviewWidth == 0 {
-				m.fileModel.filePreview.width = (msg.Width - Config.SidebarWidth - (4 + (len(m.fileModel.filePanels))*2)) / (len(m.fileModel.filePanels) + 1)
-			} else {
-				if Config.FilePreviewWidth > 10 || Config.FilePreviewWidth == 1 {
-					log.Fatalln("Config file file_preview_width invalidation")
-				}
-				m.fileModel.filePreview.width = (msg.Width - Config.SidebarWidth) / Config.FilePreviewWidth
-			}
-		}
-
-		// set each file panel size and max file panel amount
-		m.fileModel.width = (msg.Width - Config.SidebarWidth - m.fileModel.filePreview.width - (4 + (len(m.fileModel.filePanels)-1)*2)) / len(m.fileModel.filePanels)
-		m.fileModel.maxFilePanel = (msg.Width - Config.SidebarWidth - m.fileModel.filePreview.width) / 20
-		for i := range m.fileModel.filePanels {
-			m.fileModel.filePanels[i].searchBar.Width = m.fileModel.width - 4
+		if Config.FilePreviewWidth > 10 || Config.FilePreviewWidth == 1 {
+			log.Fatalln("Config file file_preview_width invalidation")
 		}
 
 		// set footer size
@@ -99,7 +119,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			footerHeight--
 		}
 
-		m.mainPanelHeight = msg.Height - footerHeight + 1
+		// set each file panel size, the file preview size and max file panel amount
+		m.applyLayout()
 
 		// set help menu size
 		m.helpMenu.height = m.fullHeight - 2
@@ -112,23 +133,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.fullWidth > 95 {
 			m.helpMenu.width = 90
 		}
-
-		if m.fileModel.maxFilePanel >= 10 {
-			m.fileModel.maxFilePanel = 10
-		}
 		return m, nil
 	case tea.MouseMsg:
 		m, cmd = wheelMainAction(msg.String(), m, cmd)
+		m.mouseAction(tea.MouseEvent(msg))
 	case tea.KeyMsg:
 		if firstUse {
 			firstUse = false
 			return m, cmd
 		}
 
-		if m.typingModal.open {
+		if m.pasteConflictModal.open {
+			m.pasteConflictModalKey(msg.String())
+		} else if m.typingModal.open {
 			m.typingModalOpenKey(msg.String())
+		} else if m.contentSearchModal.open {
+			backgroundLoadCmd = m.contentSearchModalKey(msg.String())
 		} else if m.warnModal.open {
 			m.warnModalOpenKey(msg.String())
+		} else if m.diskUsageModal.open {
+			backgroundLoadCmd = m.diskUsageModalKey(msg.String())
+		} else if m.historyModal.open {
+			m.historyModalKey(msg.String())
+		} else if m.jumpModal.open {
+			m.jumpModalKey(msg.String())
+		} else if m.marksModal.open {
+			m.marksModalKey(msg.String())
+		} else if m.workspaceModal.open {
+			m.workspaceModalKey(msg.String())
+		} else if m.pendingMarkAction != noMarkAction {
+			m.pendingMarkKey(msg.String())
+		} else if m.journalModal.open {
+			m.journalModalKey(msg.String())
+		} else if m.typeAheadFind.active && isTypeAheadFindKey(msg.String()) {
+			backgroundLoadCmd = m.typeAheadFindKey(msg.String())
 		} else if m.fileModel.renaming {
 			m.renamingKey(msg.String())
 		} else if panel.searchBar.Focused() {
@@ -144,10 +182,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 		} else {
+			// Any other key ends type-ahead find and does what it normally does
+			m.closeTypeAheadFind()
+
 			// return superfile
 			if msg.String() == containsKey(msg.String(), hotkeys.Quit) {
 				for _, data := range m.processBarModel.process {
-					if data.state == inOperation && data.done != data.total {
+					if (data.state == inOperation || data.state == waitForDecision) && data.done != data.total {
 						m.confirmToQuit = true
 						m.warnModal.title = "Confirm to quit superfile"
 						m.warnModal.content = "You still have files being processed. Are you sure you want to exit?"
@@ -160,6 +201,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			cmd = m.mainKey(msg.String(), cmd)
+			if m.typeAheadFind.active {
+				backgroundLoadCmd = m.typeAheadFindTimeoutCmd()
+			}
 		}
 	}
 
@@ -173,6 +217,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.commandLine.input, cmd =  m.commandLine.input.Update(msg)
 	} else if m.typingModal.open {
 		m.typingModal.textInput, cmd = m.typingModal.textInput.Update(msg)
+		if m.typingModal.typingType == goToPathTyping {
+			m.updateGoToPathCompletions()
+		}
+	} else if m.contentSearchModal.open {
+		m.contentSearchModal.textInput, cmd = m.contentSearchModal.textInput.Update(msg)
+	} else if m.jumpModal.open {
+		m.jumpModal.textInput, cmd = m.jumpModal.textInput.Update(msg)
+		m.updateJumpModalResults()
 	}
 
 	if m.fileModel.filePanels[m.filePanelFocusIndex].cursor < 0 {
@@ -187,7 +239,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = tea.Batch(cmd, listenForChannelMessage(channel))
 	}
 
-	m.getFilePanelItems()
+	m.recordDirectoryHistory()
+	cmd = tea.Batch(cmd, backgroundLoadCmd, m.getFilePanelItems())
+	m.clearPreviewScroll()
 
 	return m, tea.Batch(cmd)
 }
@@ -197,16 +251,27 @@ func (m model) View() string {
 	if m.fullHeight < minimumHeight || m.fullWidth < minimumWidth {
 		return m.terminalSizeWarnRender()
 	}
-	if m.fileModel.width < 18 {
+	if m.narrowestFilePanelWidth() < minimumFilePanelWidth {
 		return m.terminalSizeWarnAfterFirstRender()
 	}
 	sidebar := m.sidebarRender()
 
-	filePanel := m.filePanelRender()
+	mainPanel := ""
+	if m.fileModel.millerColumns {
+		mainPanel = lipgloss.JoinHorizontal(0, sidebar, m.millerColumnsRender())
+	} else if m.fileModel.filePreview.stacked {
+		filePanel := m.filePanelRender()
+
+		filePreview := m.filePreviewPanelRender()
 
-	filePreview := m.filePreviewPanelRender()
+		mainPanel = lipgloss.JoinHorizontal(0, m.stackedSidebarRender(), lipgloss.JoinVertical(0, filePanel, filePreview))
+	} else {
+		filePanel := m.filePanelRender()
 
-	mainPanel := lipgloss.JoinHorizontal(0, sidebar, filePanel, filePreview)
+		filePreview := m.filePreviewPanelRender()
+
+		mainPanel = lipgloss.JoinHorizontal(0, sidebar, filePanel, filePreview)
+	}
 
 	processBar := m.processBarRender()
 
@@ -224,6 +289,13 @@ func (m model) View() string {
 
 	finalRender := lipgloss.JoinVertical(0, mainPanel, footer)
 
+	if m.fileDrag.dragging {
+		fileDragGhost := m.fileDragGhostRender()
+		overlayX := min(m.fileDrag.x+1, m.fullWidth-lipgloss.Width(fileDragGhost))
+		overlayY := min(m.fileDrag.y+1, m.fullHeight-lipgloss.Height(fileDragGhost))
+		finalRender = stringfunction.PlaceOverlay(overlayX, overlayY, fileDragGhost, finalRender)
+	}
+
 	// check if need pop up modal
 	if m.helpMenu.open {
 		helpMenu := m.helpMenuRender()
@@ -239,6 +311,13 @@ func (m model) View() string {
 		return stringfunction.PlaceOverlay(overlayX, overlayY, introduceModal, finalRender)
 	}
 
+	if m.pasteConflictModal.open {
+		pasteConflictModal := m.pasteConflictModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, pasteConflictModal, finalRender)
+	}
+
 	if m.typingModal.open {
 		typingModal := m.typineModalRender()
 		overlayX := m.fullWidth/2 - modalWidth/2
@@ -246,6 +325,55 @@ func (m model) View() string {
 		return stringfunction.PlaceOverlay(overlayX, overlayY, typingModal, finalRender)
 	}
 
+	if m.contentSearchModal.open {
+		contentSearchModal := m.contentSearchModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, contentSearchModal, finalRender)
+	}
+
+	if m.diskUsageModal.open {
+		diskUsageModal := m.diskUsageModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		finalRender = stringfunction.PlaceOverlay(overlayX, overlayY, diskUsageModal, finalRender)
+	}
+
+	if m.jumpModal.open {
+		jumpModal := m.jumpModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, jumpModal, finalRender)
+	}
+
+	if m.marksModal.open {
+		marksModal := m.marksModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, marksModal, finalRender)
+	}
+
+	if m.workspaceModal.open {
+		workspaceModal := m.workspaceModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, workspaceModal, finalRender)
+	}
+
+	if m.historyModal.open {
+		historyModal := m.historyModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		return stringfunction.PlaceOverlay(overlayX, overlayY, historyModal, finalRender)
+	}
+
+	if m.journalModal.open {
+		journalModal := m.journalModalRender()
+		overlayX := m.fullWidth/2 - m.helpMenu.width/2
+		overlayY := m.fullHeight/2 - m.helpMenu.height/2
+		finalRender = stringfunction.PlaceOverlay(overlayX, overlayY, journalModal, finalRender)
+	}
+
 	if m.warnModal.open {
 		warnModal := m.warnModalRender()
 		overlayX := m.fullWidth/2 - modalWidth/2
@@ -271,7 +399,7 @@ func listenForChannelMessage(msg chan channelMessage) tea.Cmd {
 				ListeningMessage = false
 				return m
 			}
-			if time.Since(progressBarLastRenderTime).Seconds() > 2 || m.processNewState.state == successful || m.processNewState.done < 2 {
+			if time.Since(progressBarLastRenderTime).Seconds() > 2 || m.processNewState.state != inOperation || m.processNewState.done < 2 {
 				ListeningMessage = false
 				progressBarLastRenderTime = time.Now()
 				return m
@@ -280,43 +408,72 @@ func listenForChannelMessage(msg chan channelMessage) tea.Cmd {
 	}
 }
 
-func (m *model) getFilePanelItems() {
-	focusPanel := m.fileModel.filePanels[m.filePanelFocusIndex]
+func (m *model) getFilePanelItems() (cmd tea.Cmd) {
+	locations := []string{}
 	for i, filePanel := range m.fileModel.filePanels {
-		var fileElenent []element
-		nowTime := time.Now()
-		if filePanel.focusType == noneFocus && nowTime.Sub(filePanel.lastTimeGetElement) < 3*time.Second {
+		locations = append(locations, filePanel.location)
+		locations = append(locations, filePanel.visibleExpandedDirectories()...)
+		locations = append(locations, filePanel.visibleFlattenedDirectories()...)
+
+		// Only get the elements again when the panel shows something else or the directory was changed
+		listingKey := fmt.Sprintf("%s\x00%s\x00%d\x00%t\x00%t\x00%t", filePanel.location, filePanel.searchBar.Value(), filePanel.searchMode, m.toggleDotFile, filePanel.viewMode == treeView, filePanel.flatten)
+		isSearching := filePanel.recursiveSearch != nil && !filePanel.recursiveSearch.done
+		if listingKey == filePanel.elementListingKey && (!filePanel.lastTimeGetElement.IsZero() || filePanel.directoryLoad != nil || isSearching) {
 			continue
 		}
 
-		focusPanelReRender := false
+		// Navigating away cancels the directory that is still loading
+		m.fileModel.filePanels[i].cancelDirectoryLoad()
+		m.fileModel.filePanels[i].cancelRecursiveSearch()
 
-		if len(focusPanel.element) > 0 {
-			if filepath.Dir(focusPanel.element[0].location) != focusPanel.location {
-				focusPanelReRender = true
-			}
+		var fileElenent []element
+		var load *directoryLoad
+		var search *recursiveSearch
+		if filePanel.searchBar.Value() != "" && filePanel.searchMode == recursiveSearchMode {
+			search = startRecursiveSearch(filePanel.location, m.toggleDotFile, filePanel.searchBar.Value())
+		} else if filePanel.searchBar.Value() != "" && filePanel.searchMode == fuzzySearchMode {
+			fileElenent = returnFolderElementBySearchString(filePanel.location, m.toggleDotFile, filePanel.searchBar.Value())
+		} else if filePanel.flatten {
+			fileElenent = filterElements(flattenFolderElement(filePanel.location, m.toggleDotFile, Config.FlattenMaxDepth), filePanel.filterMatcher())
 		} else {
-			focusPanelReRender = true
+			fileElenent, load = loadFolderElement(filePanel.location, m.toggleDotFile, getSortOptions(filePanel.location))
+			fileElenent = filePanel.listingElements(fileElenent, m.toggleDotFile)
 		}
-
-		reRenderTime := int(float64(len(filePanel.element)) / 100)
-
-		if filePanel.focusType != noneFocus && nowTime.Sub(filePanel.lastTimeGetElement) < time.Duration(reRenderTime)*time.Second && !focusPanelReRender {
-			continue
+		if load != nil && listingKey == filePanel.elementListingKey {
+			// A directory that changed keeps its entries on screen until it's loaded again
+			load.refresh = true
+			fileElenent = filePanel.element
 		}
-
-		if filePanel.searchBar.Value() != "" {
-			fileElenent = returnFolderElementBySearchString(filePanel.location, m.toggleDotFile, filePanel.searchBar.Value())
+		m.fileModel.filePanels[i].element = fileElenent
+		m.fileModel.filePanels[i].directoryLoad = load
+		m.fileModel.filePanels[i].recursiveSearch = search
+		m.fileModel.filePanels[i].lastTimeGetElement = time.Now()
+		m.fileModel.filePanels[i].elementListingKey = listingKey
+		if load != nil {
+			cmd = tea.Batch(cmd, waitForDirectoryLoad(load))
+		} else if search != nil {
+			cmd = tea.Batch(cmd, waitForRecursiveSearch(search))
 		} else {
-			fileElenent = returnFolderElement(filePanel.location, m.toggleDotFile)
+			m.fileModel.filePanels[i].moveCursorToTargetFile(m.mainPanelHeight)
 		}
-		filePanel.element = fileElenent
-		m.fileModel.filePanels[i].element = fileElenent
-		m.fileModel.filePanels[i].lastTimeGetElement = nowTime
 	}
+
+	// Watch the git directories too, so staging and checkouts update the git status
+	gitDirectories := gitStatus.update(locations)
+	if dirWatcher != nil {
+		dirWatcher.sync(append(locations, gitDirectories...))
+	}
+	return cmd
 }
 
 func (m model) quitSuperfile() {
+	if dirWatcher != nil {
+		dirWatcher.close()
+	}
+
+	m.saveSession()
+	flushFrecencyDatabase()
+
 	// cd on quit
 	if Config.CdOnQuit {
 		currentDir := m.fileModel.filePanels[m.filePanelFocusIndex].location
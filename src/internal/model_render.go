@@ -78,38 +78,53 @@ func (m model) filePanelRender() string {
 	f := make([]string, 10)
 	for i, filePanel := range m.fileModel.filePanels {
 
-		// check if cursor or render out of range
-		if filePanel.cursor > len(filePanel.element)-1 {
+		// check if cursor or render out of range (the cursor is kept while the directory is loading)
+		if filePanel.cursor > len(filePanel.element)-1 && filePanel.directoryLoad == nil {
 			filePanel.cursor = 0
 			filePanel.render = 0
 		}
 		m.fileModel.filePanels[i] = filePanel
 
-		f[i] += filePanelTopDirectoryIconStyle.Render(" "+icon.Directory+icon.Space) + filePanelTopPathStyle.Render(truncateTextBeginning(filePanel.location, m.fileModel.width-4, "...")) + "\n"
-		filePanelWidth := 0
-		footerBorderWidth := 0
-
-		if (m.fullWidth-Config.SidebarWidth-(4+(len(m.fileModel.filePanels)-1)*2))%len(m.fileModel.filePanels) != 0 && i == len(m.fileModel.filePanels)-1 {
-			if m.fileModel.filePreview.open {
-				filePanelWidth = m.fileModel.width
-			} else {
-				filePanelWidth = (m.fileModel.width + (m.fullWidth-Config.SidebarWidth-(4+(len(m.fileModel.filePanels)-1)*2))%len(m.fileModel.filePanels))
-			}
-			footerBorderWidth = m.fileModel.width + 7
-		} else {
-			filePanelWidth = m.fileModel.width
-			footerBorderWidth = m.fileModel.width + 7
+		sortString := sortOptionsString(getSortOptions(filePanel.location))
+		branchString := ""
+		if branch := gitStatus.branch(filePanel.location); branch != "" {
+			branchString = truncateTextBeginning(branch, 20, "...") + " " + icon.GitBranch + icon.Space
 		}
+		pathWidth := filePanel.width - 4 - ansi.StringWidth(branchString+sortString) - 1
+		f[i] += filePanelTopDirectoryIconStyle.Render(" "+icon.Directory+icon.Space) + filePanelTopPathStyle.Render(fmt.Sprintf("%-*s", pathWidth, truncateTextBeginning(filePanel.location, pathWidth, "..."))) + " " + filePanelTopDirectoryIconStyle.Render(branchString) + filePanelStyle.Render(sortString) + "\n"
+		filePanelWidth := filePanel.width
+		footerBorderWidth := filePanel.width + 7
 		panelModeString := ""
 		if filePanel.panelMode == browserMode {
 			panelModeString = icon.Browser + icon.Space + "Browser"
 		} else if filePanel.panelMode == selectMode {
 			panelModeString = icon.Select + icon.Space + "Select"
 		}
+		if filterIndicator := filePanel.filterIndicator(); filterIndicator != "" {
+			panelModeString += bottomMiddleBorderSplit + filterIndicator
+			// The border width is counted in bytes, so make up for the wide characters
+			footerBorderWidth += len(bottomMiddleBorderSplit+filterIndicator) - ansi.StringWidth(bottomMiddleBorderSplit+filterIndicator)
+		}
+		if filePanel.flatten {
+			panelModeString += bottomMiddleBorderSplit + "Flat"
+			footerBorderWidth += len(bottomMiddleBorderSplit) - ansi.StringWidth(bottomMiddleBorderSplit)
+		}
 
 		f[i] += filePanelDividerStyle(filePanel.focusType).Render(strings.Repeat(Config.BorderTop, filePanelWidth)) + "\n"
-		f[i] += " " + filePanel.searchBar.View() + "\n"
-		if len(filePanel.element) == 0 {
+		if m.typeAheadFind.active && i == m.filePanelFocusIndex {
+			f[i] += " " + m.typeAheadFindRender(filePanelWidth-4) + "\n"
+		} else {
+			f[i] += " " + filePanel.searchBar.View() + "\n"
+		}
+		if filePanel.loading() {
+			f[i] += filePanelStyle.Render(" " + icon.InOperation + "  Loading " + strconv.Itoa(len(filePanel.directoryLoad.elements)) + " entries...")
+			bottomBorder := generateFooterBorder(fmt.Sprintf("%s%s%s", panelModeString, bottomMiddleBorderSplit, "0/0"), footerBorderWidth)
+			f[i] = filePanelBorderStyle(m.mainPanelHeight, filePanelWidth, filePanel.focusType, bottomBorder).Render(f[i])
+		} else if len(filePanel.element) == 0 && filePanel.recursiveSearch != nil && !filePanel.recursiveSearch.done {
+			f[i] += filePanelStyle.Render(" " + icon.InOperation + "  Searching " + strconv.Itoa(filePanel.recursiveSearch.walked) + " entries...")
+			bottomBorder := generateFooterBorder(fmt.Sprintf("%s%s%s", panelModeString, bottomMiddleBorderSplit, "0/0"), footerBorderWidth)
+			f[i] = filePanelBorderStyle(m.mainPanelHeight, filePanelWidth, filePanel.focusType, bottomBorder).Render(f[i])
+		} else if len(filePanel.element) == 0 {
 			f[i] += filePanelStyle.Render(" " + icon.Error + "  No such file or directory")
 			bottomBorder := generateFooterBorder(fmt.Sprintf("%s%s%s", panelModeString, bottomMiddleBorderSplit, "0/0"), footerBorderWidth)
 			f[i] = filePanelBorderStyle(m.mainPanelHeight, filePanelWidth, filePanel.focusType, bottomBorder).Render(f[i])
@@ -127,12 +142,20 @@ func (m model) filePanelRender() string {
 				isItemSelected := arrayContains(filePanel.selected, filePanel.element[h].location)
 				if filePanel.renaming && h == filePanel.cursor {
 					f[i] += filePanel.rename.View() + endl
+				} else if filePanel.viewMode == treeView {
+					treeGuide := filePanel.element[h].treeGuide
+					f[i] += filePanelCursorStyle.Render(cursor) + gitStatusMarker(filePanel.element[h].location) + filePanelTreeGuideStyle.Render(treeGuide) + prettierName(filePanel.element[h].name, filePanel.width-5-ansi.StringWidth(treeGuide), filePanel.element[h].directory, isItemSelected, filePanelBGColor) + endl
+				} else if filePanel.viewMode == detailsView {
+					f[i] += filePanelCursorStyle.Render(cursor) + gitStatusMarker(filePanel.element[h].location) + detailsViewRender(filePanel.element[h], filePanel.width-3, isItemSelected) + endl
 				} else {
-					f[i] += filePanelCursorStyle.Render(cursor+" ") + prettierName(filePanel.element[h].name, m.fileModel.width-5, filePanel.element[h].directory, isItemSelected, filePanelBGColor) + endl
+					f[i] += filePanelCursorStyle.Render(cursor) + gitStatusMarker(filePanel.element[h].location) + prettierName(filePanel.element[h].name, filePanel.width-5, filePanel.element[h].directory, isItemSelected, filePanelBGColor) + endl
 				}
 			}
 			cursorPosition := strconv.Itoa(filePanel.cursor + 1)
 			totalElement := strconv.Itoa(len(filePanel.element))
+			if filePanel.recursiveSearch != nil && !filePanel.recursiveSearch.done {
+				totalElement += " " + icon.InOperation
+			}
 
 			bottomBorder := generateFooterBorder(fmt.Sprintf("%s%s%s/%s", panelModeString, bottomMiddleBorderSplit, cursorPosition, totalElement), footerBorderWidth)
 			f[i] = filePanelBorderStyle(m.mainPanelHeight, filePanelWidth, filePanel.focusType, bottomBorder).Render(f[i])
@@ -204,6 +227,8 @@ func (m model) processBarRender() string {
 			symbol = processInOperationStyle.Render(icon.InOperation)
 		case cancel:
 			symbol = processCancelStyle.Render(icon.Error)
+		case waitForDecision:
+			symbol = processInOperationStyle.Render(icon.Warn)
 		}
 
 		processRender += cursor + footerStyle.Render(truncateText(process.name, footerWidth(m.fullWidth)-7, "...")+" ") + symbol + "\n"
@@ -376,18 +401,34 @@ func (m model) terminalSizeWarnAfterFirstRender() string {
 
 func (m model) typineModalRender() string {
 	previewPath := m.typingModal.location + "/" + m.typingModal.textInput.Value()
+	confirmText := " Create "
+	// The go to modal shows the path it opens and the directories completing it
+	completions := ""
+	if m.typingModal.typingType == goToPathTyping {
+		previewPath = expandGoToPath(m.typingModal.location, m.typingModal.textInput.Value())
+		confirmText = " Go "
+		if m.typingModal.err != "" {
+			completions = " " + icon.Error + "  " + m.typingModal.err
+		} else if len(m.typingModal.completions) > 0 {
+			completions = strings.Join(m.typingModal.completions, "/  ") + "/"
+		}
+		if ansi.StringWidth(completions) > modalWidth-4 {
+			completions = truncateText(completions, modalWidth-4, "...")
+		}
+		completions = modalStyle.Render(completions)
+	}
 
 	fileLocation := filePanelTopDirectoryIconStyle.Render(" "+icon.Directory+icon.Space) +
 		filePanelTopPathStyle.Render(truncateTextBeginning(previewPath, modalWidth-4, "...")) + "\n"
 
-	confirm := modalConfirm.Render(" (" + hotkeys.ConfirmTyping[0] + ") Create ")
+	confirm := modalConfirm.Render(" (" + hotkeys.ConfirmTyping[0] + ")" + confirmText)
 	cancel := modalCancel.Render(" (" + hotkeys.CancelTyping[0] + ") Cancel ")
 
 	tip := confirm +
 		lipgloss.NewStyle().Background(modalBGColor).Render("           ") +
 		cancel
 
-	return modalBorderStyle(modalHeight, modalWidth).Render(fileLocation + "\n" + m.typingModal.textInput.View() + "\n\n" + tip)
+	return modalBorderStyle(modalHeight, modalWidth).Render(fileLocation + "\n" + m.typingModal.textInput.View() + "\n" + completions + "\n" + tip)
 }
 
 func (m model) introduceModalRender() string {
@@ -406,6 +447,9 @@ func (m model) warnModalRender() string {
 	confirm := modalConfirm.Render(" (" + hotkeys.Confirm[0] + ") Confirm ")
 	cancel := modalCancel.Render(" (" + hotkeys.Quit[0] + ") Cancel ")
 	tip := confirm + lipgloss.NewStyle().Background(modalBGColor).Render("           ") + cancel
+	if m.warnModal.warnType == noticeWarn && !m.confirmToQuit {
+		tip = modalConfirm.Render(" (" + hotkeys.Confirm[0] + ") OK ")
+	}
 	return modalBorderStyle(modalHeight, modalWidth).Render(title + "\n\n" + content + "\n\n" + tip)
 }
 
@@ -495,8 +539,7 @@ func (m model) helpMenuRender() string {
 }
 
 func (m model) filePreviewPanelRender() string {
-	previewLine := m.mainPanelHeight + 2
-	m.fileModel.filePreview.width += m.fullWidth - Config.SidebarWidth - m.fileModel.filePreview.width - ((m.fileModel.width + 2) * len(m.fileModel.filePanels)) - 2
+	previewLine := m.fileModel.filePreview.height
 
 	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
 	box := filePreviewBox(previewLine, m.fileModel.filePreview.width)
@@ -583,9 +626,14 @@ func (m model) filePreviewPanelRender() string {
 
 		scanner := bufio.NewScanner(file)
 		lineCount := 0
+		skipLine := m.fileModel.filePreview.firstLine(itemPath)
 
-		maxLineLength := m.fileModel.width + 20
+		maxLineLength := m.fileModel.filePreview.width + 20
 		for scanner.Scan() {
+			if skipLine > 0 {
+				skipLine--
+				continue
+			}
 			line := scanner.Text()
 			if len(line) > maxLineLength {
 				line = line[:maxLineLength]
@@ -629,8 +677,13 @@ func (m model) filePreviewPanelRender() string {
 
 			scanner := bufio.NewScanner(file)
 			lineCount := 0
+			skipLine := m.fileModel.filePreview.firstLine(itemPath)
 
 			for scanner.Scan() {
+				if skipLine > 0 {
+					skipLine--
+					continue
+				}
 				fileContent += scanner.Text() + "\n"
 				lineCount++
 				if previewLine > 0 && lineCount >= previewLine {
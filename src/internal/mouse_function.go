@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Two clicks on the same entry within this time are a double click
+const doubleClickInterval = 500 * time.Millisecond
+
+// Rows of a file panel above its first entry: top border, path, divider and search bar
+const filePanelElementTop = 4
+
+// Part of the screen a click landed on
+type mouseTarget int
+
+const (
+	noMouseTarget mouseTarget = iota
+	sidebarMouseTarget
+	filePanelMouseTarget
+	processBarMouseTarget
+	metadataMouseTarget
+)
+
+// The last click, to tell double clicks apart
+type mouseClick struct {
+	at     time.Time
+	target mouseTarget
+	panel  int
+	index  int
+}
+
+// Return the part of the screen at the position, and the index of the file panel for file panels
+func (m model) mouseTargetAt(x int, y int) (mouseTarget, int) {
+	mainHeight := m.mainPanelHeight + 2
+	if m.fileModel.filePreview.stacked {
+		mainHeight += m.fileModel.filePreview.height
+	}
+	if y >= mainHeight {
+		switch {
+		case x < footerWidth(m.fullWidth)+2:
+			return processBarMouseTarget, 0
+		case x < 2*(footerWidth(m.fullWidth)+2):
+			return metadataMouseTarget, 0
+		}
+		return noMouseTarget, 0
+	}
+
+	sidebarWidth := sidebarOuterWidth()
+	if x < sidebarWidth {
+		return sidebarMouseTarget, 0
+	}
+
+	if m.fileModel.millerColumns {
+		parentWidth, panelWidth, _ := m.millerColumnsWidths()
+		if x >= sidebarWidth+parentWidth+2 && x < sidebarWidth+parentWidth+panelWidth+4 {
+			return filePanelMouseTarget, m.filePanelFocusIndex
+		}
+		return noMouseTarget, 0
+	}
+
+	// The file preview is below the file panels when stacked
+	if y >= m.mainPanelHeight+2 {
+		return noMouseTarget, 0
+	}
+	for i, panel := range m.fileModel.filePanels {
+		if x < m.filePanelX(i)+panel.width+2 {
+			return filePanelMouseTarget, i
+		}
+	}
+	return noMouseTarget, 0
+}
+
+// Return the index of the entry of the file panel at the row, or -1
+func (m model) filePanelElementAt(panelIndex int, y int) int {
+	panel := m.fileModel.filePanels[panelIndex]
+	row := y - filePanelElementTop
+	if row < 0 || row >= panelElementHeight(m.mainPanelHeight) || panel.loading() {
+		return -1
+	}
+	if index := panel.render + row; index < len(panel.element) {
+		return index
+	}
+	return -1
+}
+
+// Return the index of the sidebar directory at the row, or -1. Follows the layout of sidebarRender
+func (m model) sidebarDirectoryAt(y int) int {
+	// The first directory is below the top border, the title and an empty line
+	line := y - 1
+	totalHeight := 2
+	for i := m.sidebarModel.renderIndex; i < len(m.sidebarModel.directories); i++ {
+		if totalHeight >= m.mainPanelHeight {
+			break
+		}
+		location := m.sidebarModel.directories[i].location
+		if location == "Pinned+-*/=?" || location == "Disks+-*/=?" {
+			totalHeight += 3
+			continue
+		}
+		if totalHeight == line {
+			return i
+		}
+		totalHeight++
+	}
+	return -1
+}
+
+// Return whether a modal or prompt is open, clicks are ignored while it is
+func (m model) mouseBlocked() bool {
+	return firstUse || m.pasteConflictModal.open || m.typingModal.open || m.contentSearchModal.open || m.diskUsageModal.open ||
+		m.historyModal.open || m.journalModal.open || m.jumpModal.open || m.marksModal.open || m.workspaceModal.open ||
+		m.warnModal.open || m.helpMenu.open || m.confirmToQuit || m.fileModel.renaming ||
+		m.commandLine.input.Focused() || m.fileModel.filePanels[m.filePanelFocusIndex].searchBar.Focused()
+}
+
+// Focus the part of the screen that was clicked and move the cursor to the clicked entry.
+// Double clicks open the entry, ctrl clicks select it in select mode
+func (m *model) mouseClickAction(msg tea.MouseEvent) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || m.mouseBlocked() {
+		return
+	}
+
+	target, panelIndex := m.mouseTargetAt(msg.X, msg.Y)
+	click := mouseClick{at: time.Now(), target: target, panel: panelIndex, index: -1}
+	if target == sidebarMouseTarget {
+		click.index = m.sidebarDirectoryAt(msg.Y)
+	} else if target == filePanelMouseTarget {
+		click.index = m.filePanelElementAt(panelIndex, msg.Y)
+	}
+	doubleClick := click.index >= 0 && isDoubleClick(m.lastClick, click)
+	m.lastClick = click
+	if doubleClick {
+		// A third click starts over
+		m.lastClick.at = time.Time{}
+	}
+
+	switch target {
+	case sidebarMouseTarget:
+		m.focusPanel = sidebarFocus
+		m.fileModel.filePanels[m.filePanelFocusIndex].focusType = secondFocus
+		if click.index >= 0 {
+			m.sidebarModel.cursor = click.index
+			if doubleClick {
+				m.sidebarSelectDirectory()
+			}
+		}
+	case filePanelMouseTarget:
+		m.fileModel.filePanels[m.filePanelFocusIndex].focusType = noneFocus
+		m.filePanelFocusIndex = panelIndex
+		m.focusPanel = nonePanelFocus
+		m.fileModel.filePanels[m.filePanelFocusIndex].focusType = focus
+		if click.index >= 0 {
+			m.clickFilePanelElement(click.index, doubleClick, msg.Ctrl)
+		}
+	case processBarMouseTarget:
+		m.focusPanel = processBarFocus
+		m.fileModel.filePanels[m.filePanelFocusIndex].focusType = secondFocus
+	case metadataMouseTarget:
+		m.focusPanel = metadataFocus
+		m.fileModel.filePanels[m.filePanelFocusIndex].focusType = secondFocus
+	}
+}
+
+// Move the cursor to the clicked entry of the focused file panel, then select or open it
+func (m *model) clickFilePanelElement(index int, doubleClick bool, ctrl bool) {
+	m.fileModel.filePanels[m.filePanelFocusIndex].cursor = index
+	m.fileMetaData.renderIndex = 0
+
+	if m.fileModel.filePanels[m.filePanelFocusIndex].panelMode == selectMode {
+		if ctrl {
+			m.singleItemSelect()
+		}
+	} else if doubleClick {
+		m.enterPanel()
+	}
+	// The metadata is read in the background, so only once the click is done changing the panel
+	go func() {
+		m.returnMetaData()
+	}()
+}
+
+// Return whether the click is on the same entry as the last one, quickly enough
+func isDoubleClick(last mouseClick, click mouseClick) bool {
+	return last.target == click.target && last.panel == click.panel && last.index == click.index &&
+		click.at.Sub(last.at) <= doubleClickInterval
+}
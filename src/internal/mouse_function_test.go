@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMouseClick(t *testing.T) {
+	defer func(saved ConfigType) { Config = saved }(Config)
+	Config.SidebarWidth = 20
+	elements := []element{{name: "a", location: "/a"}, {name: "b", location: "/b"}, {name: "c", location: "/c"}}
+	m := model{
+		fullWidth:       120,
+		mainPanelHeight: 30,
+		fileModel: fileModel{filePanels: []filePanel{
+			{element: elements, width: 30, focusType: focus},
+			{element: elements, width: 30, render: 1, panelMode: selectMode},
+		}},
+		sidebarModel: sidebarModel{directories: []directory{
+			{location: "/home"},
+			{location: "Pinned+-*/=?"},
+			{location: "/pinned"},
+		}},
+	}
+
+	if target, _ := m.mouseTargetAt(5, 10); target != sidebarMouseTarget {
+		t.Errorf("x 5 should be the sidebar, got %d", target)
+	}
+	if target, panel := m.mouseTargetAt(60, 10); target != filePanelMouseTarget || panel != 1 {
+		t.Errorf("x 60 should be the second file panel, got %d %d", target, panel)
+	}
+	if target, _ := m.mouseTargetAt(50, 33); target != metadataMouseTarget {
+		t.Errorf("the middle of the footer should be the metadata, got %d", target)
+	}
+	if index := m.sidebarDirectoryAt(3); index != 0 {
+		t.Errorf("the first sidebar row should be /home, got %d", index)
+	}
+	if index := m.sidebarDirectoryAt(7); index != 2 {
+		t.Errorf("the row below the pinned divider should be /pinned, got %d", index)
+	}
+
+	// The first visible entry of the second panel is b, ctrl click selects it
+	m.mouseClickAction(tea.MouseEvent{X: 60, Y: filePanelElementTop, Ctrl: true, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if m.filePanelFocusIndex != 1 || m.fileModel.filePanels[0].focusType != noneFocus || m.fileModel.filePanels[1].focusType != focus {
+		t.Error("the clicked file panel should be focused")
+	}
+	panel := m.fileModel.filePanels[1]
+	if panel.cursor != 1 || len(panel.selected) != 1 || panel.selected[0] != "/b" {
+		t.Errorf("ctrl click should select b, got cursor %d and %v", panel.cursor, panel.selected)
+	}
+
+	now := time.Now()
+	click := mouseClick{at: now, target: filePanelMouseTarget, panel: 1, index: 1}
+	if !isDoubleClick(mouseClick{at: now.Add(-100 * time.Millisecond), target: filePanelMouseTarget, panel: 1, index: 1}, click) {
+		t.Error("two quick clicks on the same entry should be a double click")
+	}
+	if isDoubleClick(mouseClick{at: now.Add(-100 * time.Millisecond), target: filePanelMouseTarget, panel: 1, index: 2}, click) {
+		t.Error("clicks on different entries should not be a double click")
+	}
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// What to do when a pasted item already exists in the destination
+type pasteConflictAction int
+
+const (
+	overwriteConflict pasteConflictAction = iota
+	skipConflict
+	renameConflict
+	keepNewerConflict
+	keepLargerConflict
+)
+
+// Options of the conflict popup, in the order they are listed
+var pasteConflictActions = []struct {
+	action      pasteConflictAction
+	description string
+}{
+	{overwriteConflict, "Overwrite the existing item"},
+	{skipConflict, "Skip, keep the existing item"},
+	{renameConflict, "Rename, keep both items"},
+	{keepNewerConflict, "Keep the newer item"},
+	{keepLargerConflict, "Keep the larger item"},
+}
+
+// A pasted item whose name is taken in the destination, the paste waits for the decision on reply
+type pasteConflict struct {
+	source      string
+	destination string
+	reply       chan pasteConflictDecision
+}
+
+type pasteConflictDecision struct {
+	action pasteConflictAction
+	// Use the same action for the next conflicts of the paste without asking
+	applyToAll bool
+}
+
+// Popup asking what to do with conflicts, the ones of other pastes wait in line
+type pasteConflictModal struct {
+	open       bool
+	conflicts  []pasteConflict
+	cursor     int
+	applyToAll bool
+}
+
+// Ask the user what to do with the pasted item and wait for the decision
+func (m model) askPasteConflict(id string, source string, destination string) pasteConflictDecision {
+	p := m.processBarModel.process[id]
+	p.state = waitForDecision
+	channel <- channelMessage{
+		messageId:       id,
+		messageType:     sendProcess,
+		processNewState: p,
+	}
+
+	reply := make(chan pasteConflictDecision, 1)
+	channel <- channelMessage{
+		messageId:   id,
+		messageType: sendPasteConflict,
+		pasteConflict: pasteConflict{
+			source:      source,
+			destination: destination,
+			reply:       reply,
+		},
+	}
+	decision := <-reply
+
+	p.state = inOperation
+	channel <- channelMessage{
+		messageId:       id,
+		messageType:     sendProcess,
+		processNewState: p,
+	}
+	m.processBarModel.process[id] = p
+	return decision
+}
+
+// Turn keep newer and keep larger into overwrite or skip for the two items
+func pasteConflictOutcome(action pasteConflictAction, source string, destination string) pasteConflictAction {
+	if action != keepNewerConflict && action != keepLargerConflict {
+		return action
+	}
+	sourceInfo, err := os.Lstat(source)
+	if err != nil {
+		return skipConflict
+	}
+	destinationInfo, err := os.Lstat(destination)
+	if err != nil {
+		return overwriteConflict
+	}
+
+	if action == keepNewerConflict {
+		if sourceInfo.ModTime().After(destinationInfo.ModTime()) {
+			return overwriteConflict
+		}
+		return skipConflict
+	}
+	if pasteConflictItemSize(source, sourceInfo) > pasteConflictItemSize(destination, destinationInfo) {
+		return overwriteConflict
+	}
+	return skipConflict
+}
+
+// Return the size of the file or the total size of the directory
+func pasteConflictItemSize(location string, info os.FileInfo) int64 {
+	if info.IsDir() {
+		return folderSize(location)
+	}
+	return info.Size()
+}
+
+// Queue the conflict, the popup shows the first one
+func (m *model) openPasteConflict(conflict pasteConflict) {
+	m.pasteConflictModal.conflicts = append(m.pasteConflictModal.conflicts, conflict)
+	m.pasteConflictModal.open = true
+}
+
+// Send the decision to the paste waiting for it and show the next conflict
+func (m *model) decidePasteConflict(action pasteConflictAction) {
+	if len(m.pasteConflictModal.conflicts) == 0 {
+		m.pasteConflictModal = pasteConflictModal{}
+		return
+	}
+	m.pasteConflictModal.conflicts[0].reply <- pasteConflictDecision{
+		action:     action,
+		applyToAll: m.pasteConflictModal.applyToAll,
+	}
+	m.pasteConflictModal.conflicts = m.pasteConflictModal.conflicts[1:]
+	m.pasteConflictModal.cursor = 0
+	m.pasteConflictModal.applyToAll = false
+	m.pasteConflictModal.open = len(m.pasteConflictModal.conflicts) > 0
+}
+
+// Confirm the selected option
+func (m *model) confirmPasteConflict() {
+	m.decidePasteConflict(pasteConflictActions[m.pasteConflictModal.cursor].action)
+}
+
+// Paste conflict modal list up
+func (m *model) pasteConflictModalListUp() {
+	if m.pasteConflictModal.cursor > 0 {
+		m.pasteConflictModal.cursor--
+	} else {
+		m.pasteConflictModal.cursor = len(pasteConflictActions) - 1
+	}
+}
+
+// Paste conflict modal list down
+func (m *model) pasteConflictModalListDown() {
+	if m.pasteConflictModal.cursor < len(pasteConflictActions)-1 {
+		m.pasteConflictModal.cursor++
+	} else {
+		m.pasteConflictModal.cursor = 0
+	}
+}
+
+// Return the size and modification time of the item, or why they are unknown
+func pasteConflictItemDetails(location string) (string, string) {
+	info, err := os.Lstat(location)
+	if err != nil {
+		return "Unknown size", "Unknown modification time"
+	}
+	return formatFileSize(pasteConflictItemSize(location, info)), info.ModTime().Format("2006-01-02 15:04:05")
+}
+
+// Render the conflict popup with the pasted and the existing item side by side
+func (m model) pasteConflictModalRender() string {
+	width := m.helpMenu.width
+	conflict := m.pasteConflictModal.conflicts[0]
+	name := filepath.Base(conflict.destination)
+
+	content := " " + filePanelTopDirectoryIconStyle.Render(icon.Warn+icon.Space) + filePanelTopPathStyle.Render(truncateText(name+" already exists", width-5, "...")) + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	columnWidth := (width - 5) / 2
+	column := func(text string) string {
+		text = truncateTextBeginning(text, columnWidth, "...")
+		return text + strings.Repeat(" ", max(0, columnWidth-ansi.StringWidth(text)))
+	}
+	sourceSize, sourceTime := pasteConflictItemDetails(conflict.source)
+	destinationSize, destinationTime := pasteConflictItemDetails(conflict.destination)
+	rows := [][2]string{
+		{"Pasted", "Existing"},
+		{filepath.Dir(conflict.source), filepath.Dir(conflict.destination)},
+		{sourceSize, destinationSize},
+		{sourceTime, destinationTime},
+	}
+	for i, row := range rows {
+		if i == 0 {
+			content += "  " + helpMenuHotkeyStyle.Render(column(row[0])) + modalStyle.Render(" │ ") + helpMenuHotkeyStyle.Render(column(row[1])) + "\n"
+			continue
+		}
+		content += modalStyle.Render("  "+column(row[0])+" │ "+column(row[1])) + "\n"
+	}
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	for i, option := range pasteConflictActions {
+		cursor := "  "
+		if i == m.pasteConflictModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+		content += cursor + modalStyle.Render(option.description) + "\n"
+	}
+
+	checkbox := "[ ]"
+	if m.pasteConflictModal.applyToAll {
+		checkbox = "[x]"
+	}
+	content += "\n" + modalStyle.Render("  "+checkbox+" (a) Apply to all conflicts of this paste") + "\n\n"
+	content += modalConfirm.Render(" ("+hotkeys.Confirm[0]+") Confirm ") + modalStyle.Render("  ") + modalCancel.Render(" ("+hotkeys.Quit[0]+") Skip ")
+
+	bottomBorder := generateFooterBorder(fmt.Sprintf("1/%d", len(m.pasteConflictModal.conflicts)), width-2)
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,174 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestPasteConflictOutcome(t *testing.T) {
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small")
+	large := filepath.Join(dir, "large")
+	if err := os.WriteFile(small, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	earlier := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(large, earlier, earlier); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		action      pasteConflictAction
+		source      string
+		destination string
+		expected    pasteConflictAction
+	}{
+		{keepNewerConflict, small, large, overwriteConflict},
+		{keepNewerConflict, large, small, skipConflict},
+		{keepLargerConflict, large, small, overwriteConflict},
+		{keepLargerConflict, small, large, skipConflict},
+		{renameConflict, small, large, renameConflict},
+	}
+	for _, tt := range tests {
+		if got := pasteConflictOutcome(tt.action, tt.source, tt.destination); got != tt.expected {
+			t.Errorf("pasteConflictOutcome(%d, %s, %s) = %d, expected %d", tt.action, filepath.Base(tt.source), filepath.Base(tt.destination), got, tt.expected)
+		}
+	}
+}
+
+func TestPasteConflictModalQueue(t *testing.T) {
+	first := make(chan pasteConflictDecision, 1)
+	second := make(chan pasteConflictDecision, 1)
+	m := model{}
+	m.openPasteConflict(pasteConflict{source: "a", destination: "b", reply: first})
+	m.openPasteConflict(pasteConflict{source: "c", destination: "d", reply: second})
+
+	m.pasteConflictModalListDown()
+	m.pasteConflictModalListDown()
+	m.pasteConflictModalKey("a")
+	m.confirmPasteConflict()
+	if decision := <-first; decision.action != renameConflict || !decision.applyToAll {
+		t.Errorf("first conflict should be renamed for all, got %+v", decision)
+	}
+	if !m.pasteConflictModal.open || m.pasteConflictModal.cursor != 0 || m.pasteConflictModal.applyToAll {
+		t.Error("the second conflict should be asked from the start")
+	}
+
+	m.decidePasteConflict(skipConflict)
+	if decision := <-second; decision.action != skipConflict || decision.applyToAll {
+		t.Errorf("second conflict should be skipped, got %+v", decision)
+	}
+	if m.pasteConflictModal.open {
+		t.Error("the popup should close after the last conflict")
+	}
+}
+
+func TestPasteWaitsForConflictDecision(t *testing.T) {
+	dir := t.TempDir()
+	defer pasteConflictTestSetup(dir)()
+	source := filepath.Join(dir, "source")
+	destination := filepath.Join(dir, "destination")
+	for _, location := range []string{source, destination} {
+		if err := os.Mkdir(location, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(location, "file.txt"), []byte(location), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pasteAnsweringConflicts(t, []string{filepath.Join(source, "file.txt")}, destination, overwriteConflict)
+	data, err := os.ReadFile(filepath.Join(destination, "file.txt"))
+	if err != nil || string(data) != source {
+		t.Errorf("the existing file should be overwritten, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(destination, "file.txt(1)")); err == nil {
+		t.Error("overwrite should not keep both files")
+	}
+}
+
+func TestPasteOverwriteKeepsExistingOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	defer pasteConflictTestSetup(dir)()
+	defer func(saved ConfigType) { Config = saved }(Config)
+	// Following the symlink loop makes the copy fail after some files are copied
+	Config.CopyFollowSymlinks = true
+	source := filepath.Join(dir, "source", "folder")
+	destination := filepath.Join(dir, "destination")
+	for _, location := range []string{source, filepath.Join(destination, "folder")} {
+		if err := os.MkdirAll(location, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(location, filepath.Base(filepath.Dir(location))+".txt"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(source, filepath.Join(source, "zloop")); err != nil {
+		t.Fatal(err)
+	}
+
+	pasteAnsweringConflicts(t, []string{source}, destination, overwriteConflict)
+	if _, err := os.Stat(filepath.Join(destination, "folder", "destination.txt")); err != nil {
+		t.Errorf("a failed paste should keep the existing directory, got %v", err)
+	}
+	if entries, _ := os.ReadDir(destination); len(entries) != 1 {
+		t.Errorf("a failed paste should leave nothing next to the existing directory, got %d entries", len(entries))
+	}
+
+	if err := os.Remove(filepath.Join(source, "zloop")); err != nil {
+		t.Fatal(err)
+	}
+	pasteAnsweringConflicts(t, []string{source}, destination, overwriteConflict)
+	if _, err := os.Stat(filepath.Join(destination, "folder", "source.txt")); err != nil {
+		t.Errorf("the directory should be overwritten, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destination, "folder", "destination.txt")); !os.IsNotExist(err) {
+		t.Error("the overwritten directory should be removed")
+	}
+	if entries, _ := os.ReadDir(destination); len(entries) != 1 {
+		t.Errorf("the overwritten directory should not be left next to the new one, got %d entries", len(entries))
+	}
+}
+
+// Keep the journal of the pastes in the directory, returns the function restoring the globals
+func pasteConflictTestSetup(dir string) func() {
+	journalFile := varibale.JournalFilea
+	varibale.JournalFilea = filepath.Join(dir, "journal.json")
+	gradientColor := theme.GradientColor
+	theme.GradientColor = []string{"#000000", "#ffffff"}
+	return func() {
+		varibale.JournalFilea = journalFile
+		theme.GradientColor = gradientColor
+	}
+}
+
+// Paste the items and answer every conflict with the action
+func pasteAnsweringConflicts(t *testing.T, items []string, location string, action pasteConflictAction) {
+	m := model{processBarModel: processBarModel{process: map[string]process{}}}
+	done := make(chan struct{})
+	go func() {
+		m.pasteItemsTo(copyItems{items: items}, location)
+		close(done)
+	}()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message := <-channel:
+			if message.messageType == sendPasteConflict {
+				message.pasteConflict.reply <- pasteConflictDecision{action: action}
+			}
+		case <-done:
+			return
+		case <-timeout:
+			t.Fatal("paste did not finish")
+		}
+	}
+}
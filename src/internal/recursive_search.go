@@ -0,0 +1,256 @@
+package internal
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lithammer/shortuuid"
+	"github.com/reinhrst/fzf-lib"
+)
+
+const (
+	// Only the best matches of a recursive search are kept
+	recursiveSearchMaxResults = 1000
+	// Walked paths are ranked this many at a time
+	recursiveSearchBatchSize = 2000
+	// Rank the walked paths at least this often, so slow filesystems still show results
+	recursiveSearchBatchInterval = 100 * time.Millisecond
+)
+
+// A search through the subtree of a directory running in the background
+type recursiveSearch struct {
+	id       string
+	location string
+	query    string
+	walked   int
+	done     bool
+	results  chan recursiveSearchMsg
+	cancel   context.CancelFunc
+}
+
+// Message with the best matches of a recursive search so far
+type recursiveSearchMsg struct {
+	searchId string
+	elements []element
+	walked   int
+	done     bool
+}
+
+// A path of the subtree that matched the query
+type recursiveSearchMatch struct {
+	element element
+	score   int
+}
+
+// Start walking the subtree of the location, the best matches are streamed in with recursiveSearchMsg
+func startRecursiveSearch(location string, displayDotFile bool, query string) *recursiveSearch {
+	ctx, cancel := context.WithCancel(context.Background())
+	search := &recursiveSearch{
+		id:       shortuuid.New(),
+		location: location,
+		query:    query,
+		results:  make(chan recursiveSearchMsg, 1),
+		cancel:   cancel,
+	}
+	go search.run(ctx, displayDotFile)
+	return search
+}
+
+// Walk the subtree and rank the paths batch by batch until it's done or cancelled
+func (search *recursiveSearch) run(ctx context.Context, displayDotFile bool) {
+	defer close(search.results)
+
+	matches := []recursiveSearchMatch{}
+	batch := []string{}
+	batchIsDirectory := []bool{}
+	batchStart := time.Now()
+	walked := 0
+
+	send := func(done bool) bool {
+		matches = rankRecursiveSearchBatch(matches, search.location, search.query, batch, batchIsDirectory)
+		batch = batch[:0]
+		batchIsDirectory = batchIsDirectory[:0]
+		batchStart = time.Now()
+
+		elements := make([]element, len(matches))
+		for i, match := range matches {
+			elements[i] = match.element
+		}
+		select {
+		case search.results <- recursiveSearchMsg{searchId: search.id, elements: elements, walked: walked, done: done}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	err := filepath.WalkDir(search.location, func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// Skip directories that can't be read instead of stopping the search
+			if entry != nil && entry.IsDir() && path != search.location {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == search.location {
+			return nil
+		}
+		if !displayDotFile && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(search.location, path)
+		if err != nil {
+			return nil
+		}
+		batch = append(batch, relativePath)
+		batchIsDirectory = append(batchIsDirectory, entry.IsDir())
+		walked++
+
+		if len(batch) >= recursiveSearchBatchSize || time.Since(batchStart) >= recursiveSearchBatchInterval {
+			if !send(false) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		outPutLog("Recursive search walk directory error", search.location, err)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	send(true)
+}
+
+// Rank a batch of relative paths with fzf and merge them into the best matches so far
+func rankRecursiveSearchBatch(matches []recursiveSearchMatch, location string, query string, batch []string, batchIsDirectory []bool) []recursiveSearchMatch {
+	if len(batch) == 0 {
+		return matches
+	}
+
+	myFzf := fzf.New(batch, fzf.DefaultOptions())
+	myFzf.Search(query)
+	result := <-myFzf.GetResultChannel()
+	myFzf.End()
+
+	for _, item := range result.Matches {
+		fileLocation := filepath.Join(location, item.Key)
+		fileInfo, err := os.Lstat(fileLocation)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, recursiveSearchMatch{
+			element: element{
+				name:      item.Key,
+				location:  fileLocation,
+				directory: batchIsDirectory[item.HayIndex],
+				matchRate: float64(item.Score),
+				info:      fileInfo,
+			},
+			score: item.Score,
+		})
+	}
+
+	// Same order as fzf: higher score first, then shorter path
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].element.name) < len(matches[j].element.name)
+	})
+	if len(matches) > recursiveSearchMaxResults {
+		matches = matches[:recursiveSearchMaxResults]
+	}
+	return matches
+}
+
+// Wait for the next results of the recursive search
+func waitForRecursiveSearch(search *recursiveSearch) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-search.results
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Show the results of a recursive search in its file panel
+func (m *model) handleRecursiveSearch(msg recursiveSearchMsg) tea.Cmd {
+	for i := range m.fileModel.filePanels {
+		search := m.fileModel.filePanels[i].recursiveSearch
+		if search == nil || search.id != msg.searchId {
+			continue
+		}
+
+		m.fileModel.filePanels[i].element = msg.elements
+		search.walked = msg.walked
+		search.done = msg.done
+		if msg.done {
+			return nil
+		}
+		return waitForRecursiveSearch(search)
+	}
+
+	// The search was cancelled, nobody is waiting for it anymore
+	return nil
+}
+
+// Cancel the recursive search of the file panel
+func (panel *filePanel) cancelRecursiveSearch() {
+	if panel.recursiveSearch != nil {
+		panel.recursiveSearch.cancel()
+		panel.recursiveSearch = nil
+	}
+}
+
+// Focus on the search bar to search the whole subtree of the current directory
+func (m *model) recursiveSearchBarFocus() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if panel.searchBar.Focused() && panel.searchMode == recursiveSearchMode {
+		panel.searchBar.Blur()
+	} else {
+		if panel.searchMode != recursiveSearchMode {
+			panel.searchBar.SetValue("")
+		}
+		panel.searchMode = recursiveSearchMode
+		panel.searchBar.Placeholder = searchBarPlaceholder(recursiveSearchMode)
+		panel.searchBar.Focus()
+	}
+
+	// config search bar width
+	panel.searchBar.Width = panel.width - 4
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Go to the directory of the recursive search result under the cursor and put the cursor on it
+func (m *model) enterRecursiveSearchResult() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if len(panel.element) == 0 {
+		return
+	}
+
+	panel.targetFile = panel.element[panel.cursor].location
+	panel.location = filepath.Dir(panel.targetFile)
+	panel.cursor = 0
+	panel.render = 0
+	panel.searchBar.SetValue("")
+	panel.searchMode = fuzzySearchMode
+	panel.searchBar.Placeholder = searchBarPlaceholder(fuzzySearchMode)
+	panel.cancelRecursiveSearch()
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
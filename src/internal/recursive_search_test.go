@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecursiveSearch(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src", "internal"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "internal", "model.go"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "src", "main.go"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, ".git", "model.go"), nil, 0644)
+
+	search := startRecursiveSearch(dir, false, "model")
+	m := model{fileModel: fileModel{filePanels: []filePanel{{location: dir, recursiveSearch: search}}}}
+	cmd := waitForRecursiveSearch(search)
+	for cmd != nil {
+		cmd = m.handleRecursiveSearch(cmd().(recursiveSearchMsg))
+	}
+
+	panel := m.fileModel.filePanels[0]
+	if !panel.recursiveSearch.done {
+		t.Fatal("recursive search should be done")
+	}
+	if len(panel.element) != 1 {
+		t.Fatalf("got %d results, expected 1 (dot directories are skipped)", len(panel.element))
+	}
+	result := panel.element[0]
+	if result.name != filepath.Join("src", "internal", "model.go") {
+		t.Errorf("result name is %q, expected the path relative to the search directory", result.name)
+	}
+	if result.location != filepath.Join(dir, "src", "internal", "model.go") {
+		t.Errorf("result location is %q", result.location)
+	}
+
+	panel.element = []element{{location: filepath.Join(dir, "a")}, {location: result.location}}
+	panel.targetFile = result.location
+	panel.moveCursorToTargetFile(20)
+	if panel.cursor != 1 || panel.targetFile != "" {
+		t.Errorf("cursor is %d, expected it on the target file", panel.cursor)
+	}
+}
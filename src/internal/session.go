@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+// The file panels and the preview when superfile was closed, saved in session.json
+type session struct {
+	FilePanels  []sessionFilePanel `json:"file_panels"`
+	FocusIndex  int                `json:"focus_index"`
+	PreviewOpen bool               `json:"preview_open"`
+}
+
+type sessionFilePanel struct {
+	Location        string                            `json:"location"`
+	Cursor          int                               `json:"cursor"`
+	Render          int                               `json:"render"`
+	CursorFile      string                            `json:"cursor_file"`
+	PanelMode       panelMode                         `json:"panel_mode"`
+	ViewMode        panelViewMode                     `json:"view_mode"`
+	Flatten         bool                              `json:"flatten"`
+	SearchValue     string                            `json:"search_value"`
+	SearchMode      searchMode                        `json:"search_mode"`
+	DirectoryRecord map[string]sessionDirectoryRecord `json:"directory_record"`
+}
+
+type sessionDirectoryRecord struct {
+	Cursor   int  `json:"cursor"`
+	Render   int  `json:"render"`
+	Expanded bool `json:"expanded"`
+}
+
+// Return the session of the model
+func (m model) currentSession() session {
+	current := session{
+		FocusIndex:  m.filePanelFocusIndex,
+		PreviewOpen: m.fileModel.filePreview.open,
+	}
+	for _, panel := range m.fileModel.filePanels {
+		savedPanel := sessionFilePanel{
+			Location:        panel.location,
+			Cursor:          panel.cursor,
+			Render:          panel.render,
+			PanelMode:       panel.panelMode,
+			ViewMode:        panel.viewMode,
+			Flatten:         panel.flatten,
+			SearchValue:     panel.searchBar.Value(),
+			SearchMode:      panel.searchMode,
+			DirectoryRecord: map[string]sessionDirectoryRecord{},
+		}
+		if panel.cursor >= 0 && panel.cursor < len(panel.element) {
+			savedPanel.CursorFile = panel.element[panel.cursor].location
+		}
+		for location, record := range panel.directoryRecord {
+			savedPanel.DirectoryRecord[location] = sessionDirectoryRecord{
+				Cursor:   record.directoryCursor,
+				Render:   record.directoryRender,
+				Expanded: record.expanded,
+			}
+		}
+		current.FilePanels = append(current.FilePanels, savedPanel)
+	}
+	return current
+}
+
+// Save the file panels and the preview so the next superfile can restore them
+func (m model) saveSession() {
+	updatedData, err := json.Marshal(m.currentSession())
+	if err != nil {
+		outPutLog("Save session function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.SessionFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save session function write superfile data error", err)
+	}
+}
+
+// Load the saved session, false when there is no session to restore
+func loadSession() (session, bool) {
+	saved := session{}
+	jsonData, err := os.ReadFile(varibale.SessionFilea)
+	if err != nil {
+		outPutLog("Load session function read superfile data error", err)
+		return saved, false
+	}
+
+	if len(jsonData) == 0 {
+		return saved, false
+	}
+
+	err = json.Unmarshal(jsonData, &saved)
+	if err != nil {
+		outPutLog("Load session function unmarshal superfile data error", err)
+		return saved, false
+	}
+	return saved, len(saved.FilePanels) > 0
+}
+
+// Return the location, or its closest parent directory that still exists
+func existingDirectory(location string) string {
+	for {
+		if info, err := os.Stat(location); err == nil && info.IsDir() {
+			return location
+		}
+		parent := filepath.Dir(location)
+		if parent == location {
+			return varibale.HomeDir
+		}
+		location = parent
+	}
+}
+
+// Replace the file panels and the preview with the saved session
+func (m *model) restoreSession(saved session) {
+	filePanels := []filePanel{}
+	for _, savedPanel := range saved.FilePanels {
+		panel := filePanel{
+			location:        existingDirectory(savedPanel.Location),
+			cursor:          savedPanel.Cursor,
+			render:          savedPanel.Render,
+			panelMode:       savedPanel.PanelMode,
+			viewMode:        savedPanel.ViewMode,
+			flatten:         savedPanel.Flatten,
+			focusType:       noneFocus,
+			searchMode:      savedPanel.SearchMode,
+			directoryRecord: make(map[string]directoryRecord),
+			searchBar:       generateSearchBar(),
+			targetFile:      savedPanel.CursorFile,
+		}
+		// The cursor index is only right when the directory still exists
+		if panel.location != savedPanel.Location {
+			panel.cursor = 0
+			panel.render = 0
+			panel.targetFile = ""
+		}
+		panel.searchBar.Placeholder = searchBarPlaceholder(panel.searchMode)
+		panel.searchBar.SetValue(savedPanel.SearchValue)
+		for location, record := range savedPanel.DirectoryRecord {
+			panel.directoryRecord[location] = directoryRecord{
+				directoryCursor: record.Cursor,
+				directoryRender: record.Render,
+				expanded:        record.Expanded,
+			}
+		}
+		filePanels = append(filePanels, panel)
+	}
+
+	m.fileModel.filePanels = filePanels
+	m.filePanelFocusIndex = min(max(saved.FocusIndex, 0), len(filePanels)-1)
+	m.fileModel.filePanels[m.filePanelFocusIndex].focusType = focus
+	m.fileModel.filePreview.open = saved.PreviewOpen
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := varibale.SessionFilea
+	varibale.SessionFilea = filepath.Join(dir, "session.json")
+	defer func() { varibale.SessionFilea = sessionFile }()
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.SearchBar = []string{"/"}
+	hotkeys.FilterBar = []string{"F"}
+	for _, name := range []string{"a", "b/deleted"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	searchBar := generateSearchBar()
+	searchBar.SetValue("*.go")
+	m := model{filePanelFocusIndex: 1, fileModel: fileModel{filePanels: []filePanel{
+		{
+			location:        filepath.Join(dir, "a"),
+			cursor:          1,
+			element:         []element{{location: filepath.Join(dir, "a/x")}, {location: filepath.Join(dir, "a/y")}},
+			directoryRecord: map[string]directoryRecord{dir: {directoryCursor: 2, directoryRender: 1, expanded: true}},
+			searchBar:       searchBar,
+			searchMode:      filterSearchMode,
+			viewMode:        detailsView,
+		},
+		{
+			location:        filepath.Join(dir, "b/deleted"),
+			cursor:          3,
+			panelMode:       selectMode,
+			directoryRecord: map[string]directoryRecord{},
+			searchBar:       generateSearchBar(),
+		},
+	}, filePreview: filePreviewPanel{open: true}}}
+	m.saveSession()
+	if err := os.Remove(filepath.Join(dir, "b/deleted")); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, ok := loadSession()
+	if !ok {
+		t.Fatal("the saved session should be loaded")
+	}
+	restored := model{}
+	restored.restoreSession(saved)
+
+	panels := restored.fileModel.filePanels
+	if len(panels) != 2 || restored.filePanelFocusIndex != 1 || !restored.fileModel.filePreview.open {
+		t.Fatalf("panels, focus and preview should be restored, got %d panels focused on %d", len(panels), restored.filePanelFocusIndex)
+	}
+	if panels[1].focusType != focus || panels[0].focusType != noneFocus {
+		t.Error("only the focused file panel should be focused")
+	}
+	first := panels[0]
+	if first.cursor != 1 || first.targetFile != filepath.Join(dir, "a/y") || first.viewMode != detailsView {
+		t.Errorf("cursor and view mode should be restored, got %d %s %d", first.cursor, first.targetFile, first.viewMode)
+	}
+	if first.searchBar.Value() != "*.go" || first.searchMode != filterSearchMode {
+		t.Errorf("search should be restored, got %q %d", first.searchBar.Value(), first.searchMode)
+	}
+	if first.directoryRecord[dir].directoryCursor != 2 || !first.directoryRecord[dir].expanded {
+		t.Errorf("directory record should be restored, got %v", first.directoryRecord)
+	}
+	if panels[1].location != filepath.Join(dir, "b") || panels[1].cursor != 0 || panels[1].panelMode != selectMode {
+		t.Errorf("deleted directory should fall back to its parent, got %s at %d", panels[1].location, panels[1].cursor)
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+// Sort options of every directory, loaded from sortOptions.json
+var directorySortOptions = map[string]sortOptions{}
+
+// Load the saved sort options of all directories
+func loadSortOptions() {
+	jsonData, err := os.ReadFile(varibale.SortOptionsFilea)
+	if err != nil {
+		outPutLog("Load sort options function read superfile data error", err)
+		return
+	}
+
+	if len(jsonData) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(jsonData, &directorySortOptions)
+	if err != nil {
+		outPutLog("Load sort options function unmarshal superfile data error", err)
+	}
+}
+
+// Save the sort options of all directories
+func saveSortOptions() {
+	updatedData, err := json.Marshal(directorySortOptions)
+	if err != nil {
+		outPutLog("Save sort options function marshal superfile data error", err)
+		return
+	}
+
+	err = os.WriteFile(varibale.SortOptionsFilea, updatedData, 0644)
+	if err != nil {
+		outPutLog("Save sort options function write superfile data error", err)
+	}
+}
+
+// Return the sort options of the directory (default is sort by name)
+func getSortOptions(location string) sortOptions {
+	options, hasOptions := directorySortOptions[location]
+	if !hasOptions {
+		return sortOptions{SortType: sortByName}
+	}
+	return options
+}
+
+// Set the sort options of the directory and save it
+func setSortOptions(location string, options sortOptions) {
+	if options == (sortOptions{SortType: sortByName}) {
+		delete(directorySortOptions, location)
+	} else {
+		directorySortOptions[location] = options
+	}
+	saveSortOptions()
+}
+
+// Return the name of the sort options for the panel header
+func sortOptionsString(options sortOptions) string {
+	name := ""
+	switch options.SortType {
+	case sortByName:
+		name = "Name"
+	case sortByNaturalName:
+		name = "Natural"
+	case sortBySize:
+		name = "Size"
+	case sortByModifyTime:
+		name = "Date"
+	case sortByExtension:
+		name = "Type"
+	}
+
+	if options.Reversed {
+		return name + " ↓"
+	}
+	return name + " ↑"
+}
+
+// Sort elements with the sort options, directories always come first
+func sortElements(elements []element, options sortOptions) {
+	sort.SliceStable(elements, func(i, j int) bool {
+		if elements[i].directory != elements[j].directory {
+			return elements[i].directory
+		}
+		if options.Reversed {
+			return elementLess(elements[j], elements[i], options.SortType)
+		}
+		return elementLess(elements[i], elements[j], options.SortType)
+	})
+}
+
+func elementLess(a element, b element, sortType sortType) bool {
+	switch sortType {
+	case sortByNaturalName:
+		return naturalLess(a.name, b.name)
+	case sortBySize:
+		if a.info != nil && b.info != nil && a.info.Size() != b.info.Size() {
+			return a.info.Size() < b.info.Size()
+		}
+	case sortByModifyTime:
+		if a.info != nil && b.info != nil && !a.info.ModTime().Equal(b.info.ModTime()) {
+			return a.info.ModTime().Before(b.info.ModTime())
+		}
+	case sortByExtension:
+		extA := strings.ToLower(filepath.Ext(a.name))
+		extB := strings.ToLower(filepath.Ext(b.name))
+		if extA != extB {
+			return extA < extB
+		}
+	}
+	return a.name < b.name
+}
+
+// Compare two strings the way a human would, so "file2" comes before "file10"
+// and "v1.9" comes before "v1.10"
+func naturalLess(a string, b string) bool {
+	runesA := []rune(a)
+	runesB := []rune(b)
+	i, j := 0, 0
+
+	for i < len(runesA) && j < len(runesB) {
+		if unicode.IsDigit(runesA[i]) && unicode.IsDigit(runesB[j]) {
+			startA, startB := i, j
+			for i < len(runesA) && unicode.IsDigit(runesA[i]) {
+				i++
+			}
+			for j < len(runesB) && unicode.IsDigit(runesB[j]) {
+				j++
+			}
+
+			numberA := strings.TrimLeft(string(runesA[startA:i]), "0")
+			numberB := strings.TrimLeft(string(runesB[startB:j]), "0")
+			if len(numberA) != len(numberB) {
+				return len(numberA) < len(numberB)
+			}
+			if numberA != numberB {
+				return numberA < numberB
+			}
+			continue
+		}
+
+		lowerA := unicode.ToLower(runesA[i])
+		lowerB := unicode.ToLower(runesB[j])
+		if lowerA != lowerB {
+			return lowerA < lowerB
+		}
+		i++
+		j++
+	}
+
+	if len(runesA)-i != len(runesB)-j {
+		return len(runesA)-i < len(runesB)-j
+	}
+	return a < b
+}
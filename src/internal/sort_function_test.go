@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNaturalLess(t *testing.T) {
+	var inputs = []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{"file2", "file10", true},
+		{"file10", "file2", false},
+		{"v1.9", "v1.10", true},
+		{"v1.10.1", "v1.10", false},
+		{"File", "file2", true},
+		{"a", "B", true},
+		{"file02", "file2", true},
+		{"same", "same", false},
+	}
+
+	for _, tt := range inputs {
+		t.Run(fmt.Sprintf("Compare %s with %s", tt.a, tt.b), func(t *testing.T) {
+			result := naturalLess(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortElements(t *testing.T) {
+	elements := []element{
+		{name: "b.txt"},
+		{name: "dir2", directory: true},
+		{name: "a.go"},
+		{name: "dir10", directory: true},
+	}
+
+	var inputs = []struct {
+		options  sortOptions
+		expected []string
+	}{
+		{sortOptions{SortType: sortByName}, []string{"dir10", "dir2", "a.go", "b.txt"}},
+		{sortOptions{SortType: sortByNaturalName}, []string{"dir2", "dir10", "a.go", "b.txt"}},
+		{sortOptions{SortType: sortByName, Reversed: true}, []string{"dir2", "dir10", "b.txt", "a.go"}},
+		{sortOptions{SortType: sortByExtension, Reversed: true}, []string{"dir2", "dir10", "b.txt", "a.go"}},
+	}
+
+	for _, tt := range inputs {
+		t.Run(fmt.Sprintf("Sort with %s", sortOptionsString(tt.options)), func(t *testing.T) {
+			sorted := append([]element{}, elements...)
+			sortElements(sorted, tt.options)
+			for i, name := range tt.expected {
+				if sorted[i].name != name {
+					t.Errorf("got %s at %d, expected %s", sorted[i].name, i, name)
+				}
+			}
+		})
+	}
+}
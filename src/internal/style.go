@@ -47,6 +47,7 @@ var (
 	filePanelTopDirectoryIconStyle lipgloss.Style
 	filePanelTopPathStyle          lipgloss.Style
 	filePanelItemSelectedStyle     lipgloss.Style
+	filePanelTreeGuideStyle        lipgloss.Style
 )
 
 var (
@@ -66,6 +67,14 @@ var (
 	helpMenuTitleStyle  lipgloss.Style
 )
 
+var (
+	gitModifiedStyle   lipgloss.Style
+	gitStagedStyle     lipgloss.Style
+	gitUntrackedStyle  lipgloss.Style
+	gitIgnoredStyle    lipgloss.Style
+	gitConflictedStyle lipgloss.Style
+)
+
 func LoadThemeConfig() {
 	bottomMiddleBorderSplit = Config.BorderMiddleLeft + Config.BorderBottom + Config.BorderMiddleRight
 
@@ -114,6 +123,13 @@ func LoadThemeConfig() {
 	helpMenuHotkeyColor = lipgloss.Color(theme.HelpMenuHotkey)
 	helpMenuTitleColor = lipgloss.Color(theme.HelpMenuTitle)
 
+	// Themes made before git status was added fall back to the special colors
+	gitModifiedColor = lipgloss.Color(themeColorOr(theme.GitModified, theme.Cancel))
+	gitStagedColor = lipgloss.Color(themeColorOr(theme.GitStaged, theme.Correct))
+	gitUntrackedColor = lipgloss.Color(themeColorOr(theme.GitUntracked, theme.Hint))
+	gitIgnoredColor = lipgloss.Color(themeColorOr(theme.GitIgnored, theme.SidebarDivider))
+	gitConflictedColor = lipgloss.Color(themeColorOr(theme.GitConflicted, theme.Error))
+
 	if Config.TransparentBackground {
 		transparentAllBackgroundColor()
 	}
@@ -139,6 +155,7 @@ func LoadThemeConfig() {
 	filePanelTopDirectoryIconStyle = lipgloss.NewStyle().Foreground(filePanelTopDirectoryIconColor).Background(filePanelBGColor)
 	filePanelTopPathStyle = lipgloss.NewStyle().Foreground(filePanelTopPathColor).Background(filePanelBGColor)
 	filePanelItemSelectedStyle = lipgloss.NewStyle().Foreground(filePanelItemSelectedFGColor).Background(filePanelItemSelectedBGColor)
+	filePanelTreeGuideStyle = lipgloss.NewStyle().Foreground(filePanelBorderColor).Background(filePanelBGColor)
 
 	// Sidebar Special Style
 	sidebarDividerStyle = lipgloss.NewStyle().Foreground(sidebarDividerColor).Background(sidebarBGColor)
@@ -158,6 +175,21 @@ func LoadThemeConfig() {
 	// Help Menu Style
 	helpMenuHotkeyStyle = lipgloss.NewStyle().Foreground(helpMenuHotkeyColor).Background(modalBGColor)
 	helpMenuTitleStyle = lipgloss.NewStyle().Foreground(helpMenuTitleColor).Background(modalBGColor)
+
+	// Git Status Style
+	gitModifiedStyle = lipgloss.NewStyle().Foreground(gitModifiedColor).Background(filePanelBGColor)
+	gitStagedStyle = lipgloss.NewStyle().Foreground(gitStagedColor).Background(filePanelBGColor)
+	gitUntrackedStyle = lipgloss.NewStyle().Foreground(gitUntrackedColor).Background(filePanelBGColor)
+	gitIgnoredStyle = lipgloss.NewStyle().Foreground(gitIgnoredColor).Background(filePanelBGColor)
+	gitConflictedStyle = lipgloss.NewStyle().Foreground(gitConflictedColor).Background(filePanelBGColor)
+}
+
+// Return the color of the theme, or the fallback when the theme doesn't set it
+func themeColorOr(color string, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
 }
 
 func generateGradientColor() progress.Option {
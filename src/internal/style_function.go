@@ -221,12 +221,23 @@ func generateSearchBar() textinput.Model {
 	ti.Prompt = filePanelTopDirectoryIconStyle.Render(icon.Search + icon.Space)
 	ti.Cursor.Blink = true
 	ti.PlaceholderStyle = filePanelStyle
-	ti.Placeholder = "(" + hotkeys.SearchBar[0] + ") Type something"
+	ti.Placeholder = searchBarPlaceholder(fuzzySearchMode)
 	ti.Blur()
 	ti.CharLimit = 156
 	return ti
 }
 
+// Return the search bar placeholder of the search mode
+func searchBarPlaceholder(mode searchMode) string {
+	switch mode {
+	case recursiveSearchMode:
+		return "(" + hotkeys.RecursiveSearch[0] + ") Search in all subdirectories"
+	case filterSearchMode:
+		return "(" + hotkeys.FilterBar[0] + ") Filter with *.go or re:^test_"
+	}
+	return "(" + hotkeys.SearchBar[0] + ") Type something"
+}
+
 // Generate command line in the bottom
 func generateCommandLineInputBox() textinput.Model {
 	ti := textinput.New()
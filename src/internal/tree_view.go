@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Indentation guides of the tree view
+const (
+	treeGuideBranch   = "├─ "
+	treeGuideLast     = "└─ "
+	treeGuideVertical = "│  "
+	treeGuideEmpty    = "   "
+)
+
+// Insert the entries of the expanded directories below them, with indentation guides
+func expandTreeElements(elements []element, expanded func(location string) bool, displayDotFile bool, match func(name string) bool) []element {
+	return expandTreeLevel(elements, expanded, displayDotFile, match, "", 0)
+}
+
+func expandTreeLevel(elements []element, expanded func(location string) bool, displayDotFile bool, match func(name string) bool, prefix string, depth int) []element {
+	elements = filterTreeElements(elements, displayDotFile, match)
+	result := []element{}
+	for i, item := range elements {
+		childPrefix := ""
+		item.depth = depth
+		if depth > 0 {
+			if i == len(elements)-1 {
+				item.treeGuide = prefix + treeGuideLast
+				childPrefix = prefix + treeGuideEmpty
+			} else {
+				item.treeGuide = prefix + treeGuideBranch
+				childPrefix = prefix + treeGuideVertical
+			}
+		}
+		result = append(result, item)
+
+		if item.directory && expanded(item.location) {
+			children := returnFolderElement(item.location, displayDotFile, getSortOptions(item.location))
+			result = append(result, expandTreeLevel(children, expanded, displayDotFile, match, childPrefix, depth+1)...)
+		}
+	}
+	return result
+}
+
+// Return the entries matching the filter, and the directories with matching entries somewhere
+// inside, so the matches can be reached by expanding them
+func filterTreeElements(elements []element, displayDotFile bool, match func(name string) bool) []element {
+	if match == nil {
+		return elements
+	}
+
+	filtered := []element{}
+	for _, item := range elements {
+		if match(item.name) || (item.directory && containsFilterMatch(item.location, displayDotFile, match)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Return whether an entry somewhere inside the directory matches the filter
+func containsFilterMatch(location string, displayDotFile bool, match func(name string) bool) bool {
+	found := false
+	filepath.WalkDir(location, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || path == location {
+			return nil
+		}
+		if !displayDotFile && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if match(entry.Name()) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// Return whether the directory is expanded in the tree view of the file panel
+func (panel filePanel) expanded(location string) bool {
+	return panel.directoryRecord[location].expanded
+}
+
+// Expand or collapse the directory in the tree view of the file panel, the state is kept in the
+// record of the directory
+func (panel *filePanel) setExpanded(location string, expanded bool) {
+	if panel.directoryRecord == nil {
+		panel.directoryRecord = make(map[string]directoryRecord)
+	}
+	record := panel.directoryRecord[location]
+	record.expanded = expanded
+	panel.directoryRecord[location] = record
+}
+
+// Return the entries the file panel shows of its directory: the entries matching the filter and in
+// tree view the entries of the expanded directories
+func (panel filePanel) listingElements(elements []element, displayDotFile bool) []element {
+	if panel.viewMode == treeView {
+		return expandTreeElements(elements, panel.expanded, displayDotFile, panel.filterMatcher())
+	}
+	return filterElements(elements, panel.filterMatcher())
+}
+
+// Return the expanded directories shown in the tree view of the file panel
+func (panel filePanel) visibleExpandedDirectories() []string {
+	if panel.viewMode != treeView {
+		return nil
+	}
+
+	directories := []string{}
+	for _, item := range panel.element {
+		if item.directory && panel.expanded(item.location) {
+			directories = append(directories, item.location)
+		}
+	}
+	return directories
+}
+
+// Switch the file panel between tree view and list view
+func (m *model) toggleTreeView() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if panel.viewMode == treeView {
+		panel.viewMode = listView
+	} else {
+		panel.viewMode = treeView
+	}
+	// Keep the cursor on the same entry
+	if len(panel.element) > 0 {
+		panel.targetFile = panel.element[panel.cursor].location
+	}
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Expand or collapse the directory under the cursor in tree view
+func (m *model) toggleTreeDirectory() {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	item := panel.element[panel.cursor]
+	panel.setExpanded(item.location, !panel.expanded(item.location))
+	panel.lastTimeGetElement = time.Time{}
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+}
+
+// Collapse the directory containing the entry under the cursor and move the cursor to it,
+// return false when the entry is not inside an expanded directory
+func (m *model) collapseTreeParent() bool {
+	panel := m.fileModel.filePanels[m.filePanelFocusIndex]
+	if panel.viewMode != treeView || len(panel.element) == 0 || panel.element[panel.cursor].depth == 0 {
+		return false
+	}
+
+	parentLocation := filepath.Dir(panel.element[panel.cursor].location)
+	for i := panel.cursor - 1; i >= 0; i-- {
+		if panel.element[i].location == parentLocation {
+			panel.cursor = i
+			if panel.cursor < panel.render {
+				panel.render = panel.cursor
+			}
+			break
+		}
+	}
+	panel.setExpanded(parentLocation, false)
+	panel.lastTimeGetElement = time.Time{}
+	m.fileModel.filePanels[m.filePanelFocusIndex] = panel
+	return true
+}
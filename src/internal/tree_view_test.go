@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestExpandTreeElements(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"a/x.txt", "a/b/y.txt", "c.txt"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	elements := returnFolderElement(dir, false, getSortOptions(dir))
+	expanded := map[string]bool{
+		filepath.Join(dir, "a"):   true,
+		filepath.Join(dir, "a/b"): true,
+	}
+
+	tree := expandTreeElements(elements, func(location string) bool { return expanded[location] }, false, nil)
+	expected := []struct {
+		name  string
+		depth int
+		guide string
+	}{
+		{"a", 0, ""},
+		{"b", 1, treeGuideBranch},
+		{"y.txt", 2, treeGuideVertical + treeGuideLast},
+		{"x.txt", 1, treeGuideLast},
+		{"c.txt", 0, ""},
+	}
+	if len(tree) != len(expected) {
+		t.Fatalf("got %d elements, expected %d", len(tree), len(expected))
+	}
+	for i, e := range expected {
+		if tree[i].name != e.name || tree[i].depth != e.depth || tree[i].treeGuide != e.guide {
+			t.Errorf("element %d is %q depth %d guide %q, expected %q depth %d guide %q",
+				i, tree[i].name, tree[i].depth, tree[i].treeGuide, e.name, e.depth, e.guide)
+		}
+	}
+}
+
+func TestTreeViewFilterAndExpansion(t *testing.T) {
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.SearchBar = []string{"/"}
+	frecencyFile := varibale.FrecencyFilea
+	varibale.FrecencyFilea = filepath.Join(t.TempDir(), "frecency.json")
+	defer func(database map[string]frecencyEntry) {
+		flushFrecencyDatabase()
+		varibale.FrecencyFilea = frecencyFile
+		frecencyDatabase = database
+	}(frecencyDatabase)
+	dir := t.TempDir()
+	for _, path := range []string{"a/b/y.txt", "a/x.go", "c.go", "d/.hidden/z.txt"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	searchBar := generateSearchBar()
+	searchBar.SetValue("*.txt")
+	panel := filePanel{location: dir, viewMode: treeView, searchMode: filterSearchMode, searchBar: searchBar, directoryRecord: map[string]directoryRecord{}}
+	panel.setExpanded(filepath.Join(dir, "a"), true)
+	panel.setExpanded(filepath.Join(dir, "a/b"), true)
+	tree := panel.listingElements(returnFolderElement(dir, false, getSortOptions(dir)), false)
+	names := []string{}
+	for _, item := range tree {
+		names = append(names, item.name)
+	}
+	// Directories are kept when something inside them matches, hidden directories don't count
+	if strings.Join(names, " ") != "a b y.txt" {
+		t.Errorf("the filtered tree should be a b y.txt, got %v", names)
+	}
+
+	// The expansion is kept in the record of the directory, leaving the directory keeps it
+	m := model{fileModel: fileModel{filePanels: []filePanel{{
+		location:        filepath.Join(dir, "a"),
+		viewMode:        treeView,
+		searchBar:       generateSearchBar(),
+		directoryRecord: map[string]directoryRecord{},
+	}}}}
+	m.fileModel.filePanels[0].setExpanded(filepath.Join(dir, "a"), true)
+	m.parentDirectory()
+	panel = m.fileModel.filePanels[0]
+	if panel.location != dir || !panel.expanded(filepath.Join(dir, "a")) {
+		t.Errorf("a should stay expanded after going up to %s, got %s", dir, panel.location)
+	}
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"os"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
@@ -25,6 +26,24 @@ type hotkeyType int
 
 type channelMessageType int
 
+// Type representing the view mode of the file panel
+type panelViewMode uint
+
+// Type representing the sort type of the file panel
+type sortType uint
+
+// Type representing what the search bar of the file panel does
+type searchMode uint
+
+// Type representing the git status of a file
+type gitFileStatus uint
+
+// Type representing what the letter typed after a mark hotkey does
+type markAction uint
+
+// Type representing what the typing modal is used for
+type typingModalType uint
+
 const (
 	globalType hotkeyType = iota
 	normalType
@@ -33,6 +52,7 @@ const (
 
 const (
 	confirmDeleteItem warnType = iota
+	noticeWarn
 )
 
 // Constants for panel with no focus
@@ -56,18 +76,68 @@ const (
 	browserMode
 )
 
-// Constants for operation, success, cancel, failure
+// Constants for list view, details view or tree view
+const (
+	listView panelViewMode = iota
+	detailsView
+	treeView
+)
+
+// Constants for file panel sort type
+const (
+	sortByName sortType = iota
+	sortByNaturalName
+	sortBySize
+	sortByModifyTime
+	sortByExtension
+)
+
+// Constants for fuzzy search in the directory, recursive search in the subtree or filtering the directory
+const (
+	fuzzySearchMode searchMode = iota
+	recursiveSearchMode
+	filterSearchMode
+)
+
+// Constants for git status, a directory shows the highest status of its files
+const (
+	gitUnmodified gitFileStatus = iota
+	gitIgnored
+	gitUntracked
+	gitStaged
+	gitModified
+	gitConflicted
+)
+
+// Constants for the letter typed after a mark hotkey
+const (
+	noMarkAction markAction = iota
+	setMarkAction
+	jumpToMarkAction
+)
+
+// Constants for what the typing modal is used for
+const (
+	createItemTyping typingModalType = iota
+	goToPathTyping
+)
+
+// Constants for operation, success, cancel, failure and waiting for the user to decide
 const (
 	inOperation processState = iota
 	successful
 	cancel
 	failure
+	waitForDecision
 )
 
 const (
 	snedWarnModal channelMessageType = iota
 	sendMetadata
 	sendProcess
+	sendDirectoryChange
+	sendGitStatus
+	sendPasteConflict
 )
 
 // Main model
@@ -78,6 +148,21 @@ type model struct {
 	focusPanel          focusPanelType
 	copyItems           copyItems
 	typingModal         typingModal
+	contentSearchModal  contentSearchModal
+	diskUsageModal      diskUsageModal
+	historyModal        historyModal
+	journalModal        journalModal
+	pasteConflictModal  pasteConflictModal
+	jumpModal           jumpModal
+	marksModal          marksModal
+	pendingMarkAction   markAction
+	workspaceModal      workspaceModal
+	typeAheadFind       typeAheadFind
+	lastClick           mouseClick
+	fileDrag            fileDrag
+	borderDrag          borderDrag
+	currentWorkspace    string
+	workspaceStates     map[string]workspaceState
 	warnModal           warnModal
 	helpMenu            helpMenuModal
 	fileMetaData        fileMetadata
@@ -120,9 +205,52 @@ type warnModal struct {
 }
 
 type typingModal struct {
+	location    string
+	open        bool
+	textInput   textinput.Model
+	typingType  typingModalType
+	query       string
+	completions []string
+	err         string
+}
+
+// Modal for searching the content of the files under a directory
+type contentSearchModal struct {
+	open        bool
+	location    string
+	textInput   textinput.Model
+	pattern     string
+	err         string
+	search      *contentSearch
+	results     []contentSearchResult
+	cursor      int
+	renderIndex int
+}
+
+// A line found by the content search
+type contentSearchResult struct {
+	location string
+	line     int
+	snippet  string
+}
+
+// Modal of the disk usage analyzer
+type diskUsageModal struct {
+	open          bool
+	location      string
+	scan          *diskUsageScan
+	entries       []diskUsageEntry
+	total         int64
+	cursor        int
+	renderIndex   int
+}
+
+// An entry of the disk usage analyzer with its cumulative size
+type diskUsageEntry struct {
+	name      string
 	location  string
-	open      bool
-	textInput textinput.Model
+	directory bool
+	size      int64
 }
 
 // File metadata
@@ -141,15 +269,23 @@ type copyItems struct {
 // Model for file windows
 type fileModel struct {
 	filePanels   []filePanel
-	width        int
 	renaming     bool
 	maxFilePanel int
 	filePreview  filePreviewPanel
+	ratios       layoutRatios
+	// Show the focused file panel between its parent directory and the file preview
+	millerColumns bool
 }
 
 type filePreviewPanel struct {
-	open  bool
-	width int
+	open   bool
+	width  int
+	height int
+	// Below the file panels instead of next to them, on narrow terminals
+	stacked bool
+	// Show the file from this line on, set when a content search result is opened
+	scrollLocation string
+	scrollLine     int
 }
 
 // Panel representing a file
@@ -158,20 +294,94 @@ type filePanel struct {
 	render             int
 	focusType          filePanelFocusType
 	location           string
+	width              int
 	panelMode          panelMode
+	viewMode           panelViewMode
+	flatten            bool
 	selected           []string
 	element            []element
 	directoryRecord    map[string]directoryRecord
+	history            []directoryHistoryEntry
+	historyIndex       int
 	rename             textinput.Model
 	renaming           bool
 	searchBar          textinput.Model
+	searchMode         searchMode
+	recursiveSearch    *recursiveSearch
 	lastTimeGetElement time.Time
+	elementListingKey  string
+	directoryLoad      *directoryLoad
+	// The directory changed while it was loading, load it again once the load is done
+	refreshPending bool
+	// Move the cursor to this file once the elements are loaded
+	targetFile string
 }
 
 // Record for directory navigation
 type directoryRecord struct {
 	directoryCursor int
 	directoryRender int
+	// The directory is expanded in tree view
+	expanded        bool
+}
+
+// Modal for jumping to a visited directory ranked by frecency
+type jumpModal struct {
+	open        bool
+	textInput   textinput.Model
+	query       string
+	results     []string
+	cursor      int
+	renderIndex int
+}
+
+// Popup listing the marks
+type marksModal struct {
+	open        bool
+	cursor      int
+	renderIndex int
+}
+
+// Characters typed to move the cursor to the first entry starting with them
+type typeAheadFind struct {
+	active  bool
+	buffer  string
+	lastKey time.Time
+}
+
+// Popup listing the workspaces of workspaces.toml
+type workspaceModal struct {
+	open        bool
+	workspaces  []workspace
+	cursor      int
+	renderIndex int
+}
+
+// Location in the navigation history of a file panel
+type directoryHistoryEntry struct {
+	location string
+	cursor   int
+	render   int
+}
+
+// Popup listing the navigation history of the focused file panel
+type historyModal struct {
+	open        bool
+	cursor      int
+	renderIndex int
+}
+
+// Popup listing the file operations of the journal
+type journalModal struct {
+	open        bool
+	cursor      int
+	renderIndex int
+}
+
+// Sort options of a directory (saved in sortOptions.json)
+type sortOptions struct {
+	SortType sortType `json:"sort_type"`
+	Reversed bool     `json:"reversed"`
 }
 
 // Element within a file panel
@@ -181,6 +391,10 @@ type element struct {
 	directory bool
 	matchRate float64
 	metaData  [][2]string
+	info      os.FileInfo
+	// Depth and indentation guides in tree view
+	depth     int
+	treeGuide string
 }
 
 /* FILE WINDOWS TYPE END*/
@@ -227,6 +441,8 @@ type channelMessage struct {
 	processNewState process
 	warnModal       warnModal
 	metadata        [][2]string
+	location        string
+	pasteConflict   pasteConflict
 }
 
 /*PROCESS BAR internal TYPE END*/
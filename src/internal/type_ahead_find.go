@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// Type-ahead find ends when nothing is typed for this long
+const typeAheadFindTimeout = time.Second
+
+// Sent once the timeout after the last typed key passed
+type typeAheadFindTimeoutMsg struct {
+	lastKey time.Time
+}
+
+// Start type-ahead find in the focused file panel
+func (m *model) startTypeAheadFind() {
+	m.typeAheadFind = typeAheadFind{active: true, lastKey: time.Now()}
+}
+
+func (m *model) closeTypeAheadFind() {
+	m.typeAheadFind = typeAheadFind{}
+}
+
+// Return a command ending type-ahead find when no other key is typed before the timeout
+func (m *model) typeAheadFindTimeoutCmd() tea.Cmd {
+	lastKey := m.typeAheadFind.lastKey
+	return tea.Tick(typeAheadFindTimeout, func(time.Time) tea.Msg {
+		return typeAheadFindTimeoutMsg{lastKey: lastKey}
+	})
+}
+
+// End type-ahead find unless a key was typed since the timeout started
+func (m *model) handleTypeAheadFindTimeout(msg typeAheadFindTimeoutMsg) {
+	if m.typeAheadFind.active && m.typeAheadFind.lastKey.Equal(msg.lastKey) {
+		m.closeTypeAheadFind()
+	}
+}
+
+// Return whether the key is typed into the type-ahead buffer, other keys end type-ahead find
+// and do what they normally do
+func isTypeAheadFindKey(msg string) bool {
+	return len([]rune(msg)) == 1 || msg == containsKey(msg, hotkeys.CancelTyping)
+}
+
+// Add the character to the buffer and move the cursor to the first entry starting with it
+func (m *model) typeAheadFindKey(msg string) tea.Cmd {
+	if msg == containsKey(msg, hotkeys.CancelTyping) {
+		m.closeTypeAheadFind()
+		return nil
+	}
+
+	m.typeAheadFind.buffer += msg
+	m.typeAheadFind.lastKey = time.Now()
+	panel := &m.fileModel.filePanels[m.filePanelFocusIndex]
+	if index := typeAheadFindMatch(panel.element, m.typeAheadFind.buffer); index >= 0 {
+		panel.cursor = index
+		height := panelElementHeight(m.mainPanelHeight)
+		if panel.cursor < panel.render {
+			panel.render = panel.cursor
+		} else if panel.cursor >= panel.render+height {
+			panel.render = panel.cursor - height + 1
+		}
+	}
+	return m.typeAheadFindTimeoutCmd()
+}
+
+// Return the index of the first entry whose name starts with the prefix, or -1. The prefix
+// is case insensitive when it is all lowercase
+func typeAheadFindMatch(elements []element, prefix string) int {
+	ignoreCase := strings.ToLower(prefix) == prefix
+	for i, item := range elements {
+		name := filepath.Base(item.name)
+		if ignoreCase {
+			name = strings.ToLower(name)
+		}
+		if strings.HasPrefix(name, prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Render the type-ahead buffer in place of the search bar
+func (m model) typeAheadFindRender(width int) string {
+	find := "Find: " + m.typeAheadFind.buffer
+	if ansi.StringWidth(find) > width {
+		find = truncateTextBeginning(find, width, "...")
+	}
+	return filePanelTopDirectoryIconStyle.Render(icon.Search+icon.Space) + filePanelStyle.Render(find)
+}
@@ -0,0 +1,41 @@
+package internal
+
+import "testing"
+
+func TestTypeAheadFind(t *testing.T) {
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.CancelTyping = []string{"esc"}
+	elements := []element{}
+	for _, name := range []string{"README.md", "go.mod", "main.go", "Makefile", "src/makefile.go"} {
+		elements = append(elements, element{name: name})
+	}
+
+	m := model{fileModel: fileModel{filePanels: []filePanel{{element: elements}}}, mainPanelHeight: 10}
+	m.startTypeAheadFind()
+	for _, key := range []string{"m", "a", "k"} {
+		m.typeAheadFindKey(key)
+	}
+	if m.fileModel.filePanels[0].cursor != 3 || m.typeAheadFind.buffer != "mak" {
+		t.Errorf("mak should move the cursor to Makefile, got %d with %q", m.fileModel.filePanels[0].cursor, m.typeAheadFind.buffer)
+	}
+	if len(m.fileModel.filePanels[0].element) != len(elements) {
+		t.Error("type-ahead find should not filter the list")
+	}
+
+	m.typeAheadFindKey("x")
+	if m.fileModel.filePanels[0].cursor != 3 {
+		t.Error("the cursor should stay when nothing matches")
+	}
+
+	if typeAheadFindMatch(elements, "Ma") != 3 || typeAheadFindMatch(elements, "MA") != -1 {
+		t.Error("uppercase prefixes should be case sensitive")
+	}
+
+	m.typeAheadFindKey("esc")
+	if m.typeAheadFind.active {
+		t.Error("esc should end type-ahead find")
+	}
+	if !isTypeAheadFindKey("a") || isTypeAheadFindKey("enter") {
+		t.Error("only characters should be typed into the buffer")
+	}
+}
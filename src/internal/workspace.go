@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/exp/term/ansi"
+	"github.com/pelletier/go-toml/v2"
+	varibale "github.com/yorukot/superfile/src/config"
+	"github.com/yorukot/superfile/src/config/icon"
+)
+
+// A named set of file panels defined in workspaces.toml
+type workspace struct {
+	Name        string   `toml:"name"`
+	Panels      []string `toml:"panels"`
+	FilePreview *bool    `toml:"file_preview"`
+}
+
+type workspacesFile struct {
+	Workspace []workspace `toml:"workspace"`
+}
+
+// File panels of the workspaces we switched away from, so switching back restores them
+type workspaceState struct {
+	filePanels          []filePanel
+	filePanelFocusIndex int
+	filePreviewOpen     bool
+}
+
+// Load the workspaces defined in workspaces.toml, it is read again every time so edits apply right away
+func loadWorkspaces() []workspace {
+	data, err := os.ReadFile(varibale.WorkspacesFilea)
+	if err != nil {
+		outPutLog("Load workspaces function read workspaces file error", err)
+		return nil
+	}
+
+	workspaces := workspacesFile{}
+	err = toml.Unmarshal(data, &workspaces)
+	if err != nil {
+		outPutLog("Load workspaces function decode workspaces file error", err)
+		return nil
+	}
+
+	valid := []workspace{}
+	for _, ws := range workspaces.Workspace {
+		if ws.Name == "" || len(ws.Panels) == 0 {
+			outPutLog("Load workspaces function workspace without name or panels", ws.Name)
+			continue
+		}
+		valid = append(valid, ws)
+	}
+	return valid
+}
+
+// Return the workspace with the name
+func findWorkspace(workspaces []workspace, name string) (workspace, bool) {
+	for _, ws := range workspaces {
+		if ws.Name == name {
+			return ws, true
+		}
+	}
+	return workspace{}, false
+}
+
+// Return the file panels of the workspace, one per existing path
+func (ws workspace) filePanels() []filePanel {
+	filePanels := []filePanel{}
+	for _, path := range ws.Panels {
+		filePanels = append(filePanels, filePanel{
+			location:        existingDirectory(expandGoToPath(varibale.HomeDir, path)),
+			panelMode:       browserMode,
+			focusType:       noneFocus,
+			directoryRecord: make(map[string]directoryRecord),
+			searchBar:       generateSearchBar(),
+		})
+	}
+	return filePanels
+}
+
+// Replace all file panels with the ones of the workspace, or the ones we left it with
+func (m *model) switchWorkspace(ws workspace) {
+	if m.currentWorkspace == ws.Name {
+		return
+	}
+	for i := range m.fileModel.filePanels {
+		m.fileModel.filePanels[i].cancelDirectoryLoad()
+		m.fileModel.filePanels[i].cancelRecursiveSearch()
+	}
+	if m.workspaceStates == nil {
+		m.workspaceStates = map[string]workspaceState{}
+	}
+	m.workspaceStates[m.currentWorkspace] = workspaceState{
+		filePanels:          m.fileModel.filePanels,
+		filePanelFocusIndex: m.filePanelFocusIndex,
+		filePreviewOpen:     m.fileModel.filePreview.open,
+	}
+
+	state, ok := m.workspaceStates[ws.Name]
+	if !ok {
+		state = workspaceState{
+			filePanels:      ws.filePanels(),
+			filePreviewOpen: m.fileModel.filePreview.open,
+		}
+		if ws.FilePreview != nil {
+			state.filePreviewOpen = *ws.FilePreview
+		}
+		// Keep as many file panels as the terminal can show
+		if m.fileModel.maxFilePanel > 0 && len(state.filePanels) > m.fileModel.maxFilePanel {
+			state.filePanels = state.filePanels[:m.fileModel.maxFilePanel]
+		}
+	}
+	m.currentWorkspace = ws.Name
+	m.fileModel.filePanels = state.filePanels
+	m.filePanelFocusIndex = state.filePanelFocusIndex
+	m.fileModel.filePreview.open = state.filePreviewOpen
+	for i := range m.fileModel.filePanels {
+		m.fileModel.filePanels[i].focusType = noneFocus
+		// Load the elements again, the directories may have changed while the workspace was hidden
+		m.fileModel.filePanels[i].elementListingKey = ""
+	}
+	m.fileModel.filePanels[m.filePanelFocusIndex].focusType = returnFocusType(m.focusPanel)
+
+	// Before the first window size the layout is applied once the size is known
+	if m.fullWidth > 0 {
+		m.applyLayout()
+	}
+}
+
+// Open the workspace picker
+func (m *model) openWorkspaceModal() {
+	m.workspaceModal = workspaceModal{
+		open:       true,
+		workspaces: loadWorkspaces(),
+	}
+	for i, ws := range m.workspaceModal.workspaces {
+		if ws.Name == m.currentWorkspace {
+			m.workspaceModal.cursor = i
+		}
+	}
+	m.workspaceModal.renderIndex = max(0, m.workspaceModal.cursor-workspaceModalListHeight(m.helpMenu.height)+1)
+}
+
+func (m *model) closeWorkspaceModal() {
+	m.workspaceModal = workspaceModal{}
+}
+
+// Switch to the workspace selected in the workspace picker
+func (m *model) confirmWorkspaceModal() {
+	workspaces := m.workspaceModal.workspaces
+	cursor := m.workspaceModal.cursor
+	m.closeWorkspaceModal()
+	if len(workspaces) == 0 {
+		return
+	}
+	m.switchWorkspace(workspaces[cursor])
+}
+
+// Workspace modal list up
+func (m *model) workspaceModalListUp() {
+	length := len(m.workspaceModal.workspaces)
+	if m.workspaceModal.cursor > 0 {
+		m.workspaceModal.cursor--
+		if m.workspaceModal.cursor < m.workspaceModal.renderIndex {
+			m.workspaceModal.renderIndex--
+		}
+	} else if length > 0 {
+		m.workspaceModal.cursor = length - 1
+		m.workspaceModal.renderIndex = max(0, length-workspaceModalListHeight(m.helpMenu.height))
+	}
+}
+
+// Workspace modal list down
+func (m *model) workspaceModalListDown() {
+	length := len(m.workspaceModal.workspaces)
+	if m.workspaceModal.cursor < length-1 {
+		m.workspaceModal.cursor++
+		if m.workspaceModal.cursor >= m.workspaceModal.renderIndex+workspaceModalListHeight(m.helpMenu.height) {
+			m.workspaceModal.renderIndex++
+		}
+	} else {
+		m.workspaceModal.cursor = 0
+		m.workspaceModal.renderIndex = 0
+	}
+}
+
+// Lines of the workspace modal left for the workspaces
+func workspaceModalListHeight(modalHeight int) int {
+	return modalHeight - 2
+}
+
+// Render the workspace picker
+func (m model) workspaceModalRender() string {
+	width := m.helpMenu.width
+	workspaces := m.workspaceModal.workspaces
+
+	content := " " + filePanelTopDirectoryIconStyle.Render(icon.Directory+icon.Space) + filePanelTopPathStyle.Render("Workspaces") + "\n"
+	content += modalStyle.Render(strings.Repeat(Config.BorderTop, width)) + "\n"
+
+	if len(workspaces) == 0 {
+		content += modalStyle.Render(" " + icon.Error + "  No workspaces, define them in " + varibale.WorkspacesFilea)
+	}
+	for i := m.workspaceModal.renderIndex; i < m.workspaceModal.renderIndex+workspaceModalListHeight(m.helpMenu.height) && i < len(workspaces); i++ {
+		if i != m.workspaceModal.renderIndex {
+			content += "\n"
+		}
+
+		cursor := "  "
+		if i == m.workspaceModal.cursor {
+			cursor = filePanelCursorStyle.Render(icon.Cursor + " ")
+		}
+
+		name := workspaces[i].Name
+		panels := strings.Join(workspaces[i].Panels, ", ")
+		if ansi.StringWidth(name+"  "+panels) > width-6 {
+			panels = truncateText(panels, max(0, width-8-ansi.StringWidth(name)), "...")
+		}
+		if name == m.currentWorkspace {
+			name = "* " + name
+		} else {
+			name = "  " + name
+		}
+		content += cursor + helpMenuHotkeyStyle.Render(name) + modalStyle.Render("  "+panels)
+	}
+
+	count := "0/0"
+	if len(workspaces) > 0 {
+		count = fmt.Sprintf("%d/%d", m.workspaceModal.cursor+1, len(workspaces))
+	}
+	bottomBorder := generateFooterBorder(count, width-2)
+
+	return helpMenuModalBorderStyle(m.helpMenu.height, width, bottomBorder).Render(content)
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	varibale "github.com/yorukot/superfile/src/config"
+)
+
+func TestWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	workspacesFile := varibale.WorkspacesFilea
+	varibale.WorkspacesFilea = filepath.Join(dir, "workspaces.toml")
+	defer func() { varibale.WorkspacesFilea = workspacesFile }()
+	defer func(saved HotkeysType) { hotkeys = saved }(hotkeys)
+	hotkeys.SearchBar = []string{"/"}
+	for _, name := range []string{"api", "web", "pictures"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := `
+[[workspace]]
+name = "backend"
+panels = ["` + filepath.Join(dir, "api") + `", "` + filepath.Join(dir, "web") + `"]
+
+[[workspace]]
+name = "media"
+panels = ["` + filepath.Join(dir, "pictures") + `"]
+file_preview = false
+
+[[workspace]]
+name = "empty"
+`
+	if err := os.WriteFile(varibale.WorkspacesFilea, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workspaces := loadWorkspaces()
+	if len(workspaces) != 2 {
+		t.Fatalf("workspaces without panels should be skipped, got %v", workspaces)
+	}
+
+	m := model{fileModel: fileModel{
+		filePanels:  []filePanel{{location: dir, focusType: focus, directoryRecord: map[string]directoryRecord{}}},
+		filePreview: filePreviewPanel{open: true},
+	}}
+	backend, _ := findWorkspace(workspaces, "backend")
+	m.switchWorkspace(backend)
+	if len(m.fileModel.filePanels) != 2 || m.fileModel.filePanels[1].location != filepath.Join(dir, "web") {
+		t.Fatalf("backend should open api and web, got %v", m.fileModel.filePanels)
+	}
+	parent := filepath.Dir(dir)
+	m.fileModel.filePanels[1].location = parent
+	m.filePanelFocusIndex = 1
+
+	media, _ := findWorkspace(workspaces, "media")
+	m.switchWorkspace(media)
+	if len(m.fileModel.filePanels) != 1 || m.fileModel.filePreview.open || m.filePanelFocusIndex != 0 {
+		t.Errorf("media should open one panel without preview, got %d panels", len(m.fileModel.filePanels))
+	}
+
+	// Switching back restores the panels we left the workspace with
+	m.switchWorkspace(backend)
+	if m.fileModel.filePanels[1].location != parent || m.filePanelFocusIndex != 1 || !m.fileModel.filePreview.open {
+		t.Errorf("backend should be restored as we left it, got %s focused on %d", m.fileModel.filePanels[1].location, m.filePanelFocusIndex)
+	}
+	if m.fileModel.filePanels[1].focusType != focus || m.fileModel.filePanels[0].focusType != noneFocus {
+		t.Error("only the focused file panel should be focused")
+	}
+}
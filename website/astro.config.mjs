@@ -107,6 +107,10 @@ export default defineConfig({
               label: 'Custom theme',
               link: '/configure/custom-theme'
             },
+            {
+              label: 'Workspaces',
+              link: '/configure/workspaces'
+            },
             {
               label: 'Enable plugin',
               link: '/configure/enable-plugin'